@@ -0,0 +1,221 @@
+package cluster
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+	"sync"
+)
+
+// Config tells which keys are near duplicates.
+type Config struct {
+	// MaxDistance is the largest edit distance between two keys of a cluster. A cluster is a connected group, so two
+	// of its keys can be further apart if other keys link them.
+	MaxDistance int
+	// Normalize maps the keys before they're compared, like lower casing them or removing their punctuation.
+	// The keys mapped to the same string always end up in the same cluster. If nil, the keys are compared as is.
+	Normalize func(key string) string
+	// MaxMatches caps the number of matches looked for every key, which bounds the work in dense parts of the
+	// dataset. If <= 0 there's no limit.
+	MaxMatches int
+	// MinSize is the smallest number of keys of the clusters returned, if <= 0 it's 2 so that only duplicates are
+	// returned.
+	MinSize int
+	// Workers is the number of goroutines searching the keys, if <= 0 it's 1.
+	Workers int
+}
+
+// Cluster is a group of near duplicate keys.
+type Cluster struct {
+	// Representative is the key of the cluster with the most matches, the first one in sorted order on ties
+	Representative string
+	// Keys holds the distinct keys of the cluster, sorted
+	Keys []string
+}
+
+// node is a normalized key and the keys mapped to it.
+type node struct {
+	normalized string
+	keys       []string
+	// matches is the number of other keys within the distance, including the ones with the same normalized key
+	matches int
+}
+
+// Keys groups the keys that are within the distance of each other. Every distinct key is searched in a trie of all
+// the keys, and the matches are merged in a union-find, so the clusters are the connected components of the graph
+// linking the keys within the distance.
+// The clusters are sorted by decreasing size, and then by representative.
+func Keys(ctx context.Context, keys []string, config Config) ([]Cluster, error) {
+	normalize := config.Normalize
+	if normalize == nil {
+		normalize = func(key string) string {
+			return key
+		}
+	}
+
+	minSize := config.MinSize
+	if minSize <= 0 {
+		minSize = 2
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	maxMatches := config.MaxMatches
+	if maxMatches <= 0 {
+		maxMatches = -1
+	}
+
+	nodes := groupKeys(keys, normalize)
+
+	keysTrie := trie.New[int]()
+	for i := range nodes {
+		index := i
+		keysTrie.Insert(nodes[i].normalized, &index, trie.KeepFirst[int])
+	}
+
+	type nodeMatches struct {
+		index   int
+		matches []int
+	}
+
+	indexes := make(chan int, 4*workers)
+	results := make(chan nodeMatches, 4*workers)
+	doneCh := ctx.Done()
+
+	go func() {
+		defer close(indexes)
+
+		for i := range nodes {
+			select {
+			case indexes <- i:
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer waitGroup.Done()
+
+			for index := range indexes {
+				// one more result since the key matches itself
+				collector := fuzzy.NewListCollector[int](maxMatches)
+				if maxMatches > 0 {
+					collector.MaxResult++
+				}
+				fuzzy.Search[int](ctx, keysTrie, nodes[index].normalized, config.MaxDistance, collector)
+
+				result := nodeMatches{index: index}
+				for _, match := range collector.Results {
+					if *match.Value != index {
+						result.matches = append(result.matches, *match.Value)
+					}
+				}
+
+				select {
+				case results <- result:
+				case <-doneCh:
+				}
+			}
+		}()
+	}
+
+	go func() {
+		waitGroup.Wait()
+		close(results)
+	}()
+
+	sets := newUnionFind(len(nodes))
+	for result := range results {
+		nodes[result.index].matches += len(result.matches)
+		for _, match := range result.matches {
+			sets.union(result.index, match)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return buildClusters(nodes, sets, minSize), nil
+}
+
+// groupKeys returns the distinct normalized keys, with the distinct keys mapped to each of them.
+func groupKeys(keys []string, normalize func(key string) string) []node {
+	var nodes []node
+	nodeIndexes := make(map[string]int)
+	seen := make(map[string]struct{}, len(keys))
+
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		normalized := normalize(key)
+		index, ok := nodeIndexes[normalized]
+		if !ok {
+			index = len(nodes)
+			nodeIndexes[normalized] = index
+			nodes = append(nodes, node{normalized: normalized})
+		}
+
+		nodes[index].keys = append(nodes[index].keys, key)
+	}
+
+	for i := range nodes {
+		nodes[i].matches = len(nodes[i].keys) - 1
+	}
+
+	return nodes
+}
+
+func buildClusters(nodes []node, sets *unionFind, minSize int) []Cluster {
+	members := make(map[int][]int)
+	for i := range nodes {
+		root := sets.find(i)
+		members[root] = append(members[root], i)
+	}
+
+	var clusters []Cluster
+	for _, indexes := range members {
+		var cluster Cluster
+		bestMatches := -1
+
+		for _, index := range indexes {
+			cluster.Keys = append(cluster.Keys, nodes[index].keys...)
+
+			for _, key := range nodes[index].keys {
+				if nodes[index].matches > bestMatches ||
+					(nodes[index].matches == bestMatches && key < cluster.Representative) {
+					cluster.Representative = key
+					bestMatches = nodes[index].matches
+				}
+			}
+		}
+
+		if len(cluster.Keys) < minSize {
+			continue
+		}
+
+		sort.Strings(cluster.Keys)
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Keys) != len(clusters[j].Keys) {
+			return len(clusters[i].Keys) > len(clusters[j].Keys)
+		}
+
+		return clusters[i].Representative < clusters[j].Representative
+	})
+
+	return clusters
+}
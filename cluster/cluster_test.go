@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func checkClusters(t *testing.T, clusters []Cluster, expected ...string) {
+	t.Helper()
+
+	var got []string
+	for _, cluster := range clusters {
+		got = append(got, cluster.Representative+": "+strings.Join(cluster.Keys, "|"))
+	}
+
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := []string{"Saint-Étienne", "Bern", "Saint Etienne", "St Etienne", "Paris", "Berne", "Bern", "Zurich"}
+
+	for _, workers := range []int{1, 4} {
+		clusters, err := Keys(context.Background(), keys, Config{MaxDistance: 2, Workers: workers})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// St Etienne is 3 edits away from the others
+		checkClusters(t, clusters,
+			"Bern: Bern|Berne",
+			"Saint Etienne: Saint Etienne|Saint-Étienne",
+		)
+	}
+}
+
+func TestKeysAreChained(t *testing.T) {
+	// St Etienne and Saint-Étienne are 5 edits apart, but Saint Etienne links them
+	clusters, err := Keys(context.Background(), []string{"Saint-Étienne", "Saint Etienne", "St Etienne"}, Config{MaxDistance: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkClusters(t, clusters, "Saint Etienne: Saint Etienne|Saint-Étienne|St Etienne")
+}
+
+func TestKeysNormalizeAndMinSize(t *testing.T) {
+	clusters, err := Keys(context.Background(), []string{"BERN", "bern", "Paris", "Tokyo", "tokio"}, Config{
+		MaxDistance: 1,
+		Normalize:   strings.ToLower,
+		MinSize:     1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkClusters(t, clusters,
+		"BERN: BERN|bern",
+		"Tokyo: Tokyo|tokio",
+		"Paris: Paris",
+	)
+}
+
+func TestKeysCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Keys(ctx, []string{"Bern", "Berne"}, Config{MaxDistance: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestUnionFind(t *testing.T) {
+	uf := newUnionFind(6)
+	uf.union(0, 1)
+	uf.union(2, 3)
+	uf.union(1, 3)
+
+	if uf.find(0) != uf.find(2) || uf.find(4) == uf.find(0) || uf.sizes[uf.find(0)] != 4 {
+		t.Fatal("0, 1, 2 and 3 should be in the same set")
+	}
+}
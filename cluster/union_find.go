@@ -0,0 +1,53 @@
+package cluster
+
+// unionFind is a disjoint-set forest with path compression and union by size.
+type unionFind struct {
+	parents []int
+	sizes   []int
+}
+
+func newUnionFind(size int) *unionFind {
+	uf := &unionFind{
+		parents: make([]int, size),
+		sizes:   make([]int, size),
+	}
+
+	for i := range uf.parents {
+		uf.parents[i] = i
+		uf.sizes[i] = 1
+	}
+
+	return uf
+}
+
+// find returns the root of the set of i.
+func (uf *unionFind) find(i int) int {
+	root := i
+	for uf.parents[root] != root {
+		root = uf.parents[root]
+	}
+
+	// point the whole path to the root
+	for uf.parents[i] != root {
+		next := uf.parents[i]
+		uf.parents[i] = root
+		i = next
+	}
+
+	return root
+}
+
+// union merges the sets of i and j.
+func (uf *unionFind) union(i int, j int) {
+	rootI, rootJ := uf.find(i), uf.find(j)
+	if rootI == rootJ {
+		return
+	}
+
+	if uf.sizes[rootI] < uf.sizes[rootJ] {
+		rootI, rootJ = rootJ, rootI
+	}
+
+	uf.parents[rootJ] = rootI
+	uf.sizes[rootI] += uf.sizes[rootJ]
+}
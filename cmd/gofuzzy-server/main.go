@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/loader"
+	"github.com/marcadamsge/gofuzzy/server"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	dataFileName := flag.String("data", "", "dataset to index, in TSV, CSV or JSON Lines")
+	formatName := flag.String("format", "", "format of the dataset: tsv, csv or jsonl, guessed from the file extension by default")
+	header := flag.Bool("header", false, "the first line of the TSV or CSV dataset holds the names of the columns")
+	keyColumn := flag.Int("key-column", 0, "index of the TSV or CSV column to index")
+	keyField := flag.String("key-field", "name", "field of the JSON Lines records to index")
+	address := flag.String("addr", ":8080", "address to listen on")
+	timeout := flag.Duration("timeout", time.Second, "timeout of a single query")
+	maxDistance := flag.Int("max-distance", 3, "largest distance a query can ask for")
+	maxNodesVisited := flag.Int("max-nodes", 0, "maximum number of nodes a query can visit, 0 for no limit")
+	flag.Parse()
+
+	if *dataFileName == "" {
+		println("-data flag is required")
+		os.Exit(1)
+	}
+
+	var format loader.Format
+	var err error
+	if *formatName != "" {
+		format, err = loader.ParseFormat(*formatName)
+	} else {
+		format, err = loader.FormatFromFileName(*dataFileName)
+	}
+
+	if err != nil {
+		fmt.Printf("%s, use the -format flag\n", err.Error())
+		os.Exit(1)
+	}
+
+	dataFile, err := os.Open(*dataFileName)
+	if err != nil {
+		fmt.Printf("failed to open dataset with error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	startTime := time.Now()
+	dataset, report, err := server.LoadDataset(dataFile, server.DatasetConfig{
+		Format:    format,
+		Header:    *header,
+		KeyColumn: *keyColumn,
+		KeyField:  *keyField,
+	})
+	dataFile.Close()
+
+	if err != nil {
+		fmt.Printf("failed to load dataset with error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	for _, lineErr := range report.Errors {
+		fmt.Printf("skipped record: %s\n", lineErr.Error())
+	}
+
+	fmt.Printf("%d records loaded in %f seconds\n", report.Inserted, time.Now().Sub(startTime).Seconds())
+
+	config := server.DefaultConfig()
+	config.Timeout = *timeout
+	config.MaxDistance = *maxDistance
+	config.MaxNodesVisited = *maxNodesVisited
+
+	fmt.Printf("listening on %s\n", *address)
+	log.Fatal(http.ListenAndServe(*address, server.NewHandler(dataset, config)))
+}
@@ -0,0 +1,102 @@
+package main
+
+import "strings"
+
+const (
+	highlightStart = "\033[1;31m"
+	highlightEnd   = "\033[0m"
+)
+
+// editedRunes aligns the query with the key, and tells for every rune of the key if it was edited: replaced,
+// inserted or swapped with its neighbour. If prefix is true, the query is aligned with the best prefix of the key and
+// the rest of the key is left as is.
+func editedRunes(query []rune, key []rune, prefix bool) []bool {
+	// distances[i][j] is the optimal string alignment distance between query[:i] and key[:j]
+	distances := make([][]int, len(query)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(key)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(query); i++ {
+		for j := 1; j <= len(key); j++ {
+			cost := 1
+			if query[i-1] == key[j-1] {
+				cost = 0
+			}
+
+			best := distances[i-1][j-1] + cost
+			best = minInt(best, distances[i-1][j]+1)
+			best = minInt(best, distances[i][j-1]+1)
+
+			if i > 1 && j > 1 && query[i-1] == key[j-2] && query[i-2] == key[j-1] {
+				best = minInt(best, distances[i-2][j-2]+1)
+			}
+
+			distances[i][j] = best
+		}
+	}
+
+	end := len(key)
+	if prefix {
+		for j := 0; j <= len(key); j++ {
+			if distances[len(query)][j] < distances[len(query)][end] {
+				end = j
+			}
+		}
+	}
+
+	// walk the alignment back from the end
+	edited := make([]bool, len(key))
+	for i, j := len(query), end; i > 0 || j > 0; {
+		switch {
+		case i > 0 && j > 0 && query[i-1] == key[j-1] && distances[i][j] == distances[i-1][j-1]:
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && distances[i][j] == distances[i-1][j-1]+1:
+			edited[j-1] = true
+			i, j = i-1, j-1
+		case i > 1 && j > 1 && query[i-1] == key[j-2] && query[i-2] == key[j-1] && distances[i][j] == distances[i-2][j-2]+1:
+			edited[j-1] = true
+			edited[j-2] = true
+			i, j = i-2, j-2
+		case j > 0 && distances[i][j] == distances[i][j-1]+1:
+			// the rune was inserted in the key
+			edited[j-1] = true
+			j--
+		default:
+			// the rune of the query was removed from the key
+			i--
+		}
+	}
+
+	return edited
+}
+
+// highlight wraps the edited runes of the key with terminal colors.
+func highlight(key []rune, edited []bool) string {
+	var builder strings.Builder
+	for i, r := range key {
+		if edited[i] && (i == 0 || !edited[i-1]) {
+			builder.WriteString(highlightStart)
+		}
+
+		builder.WriteRune(r)
+
+		if edited[i] && (i == len(key)-1 || !edited[i+1]) {
+			builder.WriteString(highlightEnd)
+		}
+	}
+
+	return builder.String()
+}
+
+func minInt(a int, b int) int {
+	if a <= b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func checkEdited(t *testing.T, query string, key string, prefix bool, expected string) {
+	edited := editedRunes([]rune(query), []rune(key), prefix)
+
+	var actual strings.Builder
+	for _, e := range edited {
+		if e {
+			actual.WriteRune('^')
+		} else {
+			actual.WriteRune(' ')
+		}
+	}
+
+	if strings.TrimRight(actual.String(), " ") != expected {
+		t.Fatalf("query '%s' and key '%s': expected edits '%s' but got '%s'", query, key, expected, actual.String())
+	}
+}
+
+func TestEditedRunes(t *testing.T) {
+	checkEdited(t, "bern", "bern", false, "")
+	checkEdited(t, "bren", "bern", false, " ^^")
+	checkEdited(t, "bxrn", "bern", false, " ^")
+	checkEdited(t, "brn", "bern", false, " ^")
+	checkEdited(t, "bernn", "bern", false, "")
+	checkEdited(t, "ber", "berlin", true, "")
+	checkEdited(t, "bxr", "berlin", true, " ^")
+	checkEdited(t, "", "bern", false, "^^^^")
+	checkEdited(t, "⌘at", "cat", false, "^")
+}
+
+func TestHighlight(t *testing.T) {
+	highlighted := highlight([]rune("bern"), []bool{false, true, true, false})
+	if highlighted != "b"+highlightStart+"er"+highlightEnd+"n" {
+		t.Fatalf("unexpected highlight '%s'", highlighted)
+	}
+
+	highlighted = highlight([]rune("ab"), []bool{true, true})
+	if highlighted != highlightStart+"ab"+highlightEnd {
+		t.Fatalf("unexpected highlight '%s'", highlighted)
+	}
+}
+
+func TestIndexLines(t *testing.T) {
+	linesTrie, count, err := indexLines(strings.NewReader("a,bern\nb,paris\nc\nd,bern\n"), 1, ",")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 3 {
+		t.Fatalf("expected 3 lines but got %d", count)
+	}
+
+	bern := linesTrie.Step('b').Step('e').Step('r').Step('n')
+	if bern == nil || len(*bern.Value) != 2 || (*bern.Value)[1].Text != "d,bern" {
+		t.Fatal("both bern lines should of been indexed")
+	}
+
+	linesTrie, count, err = indexLines(strings.NewReader("bern\n\nparis\n"), -1, ",")
+	if err != nil || count != 2 || linesTrie.Step('p') == nil {
+		t.Fatal("the whole lines should of been indexed")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"io"
+	"strings"
+)
+
+// Line is a line of the input, indexed by Key.
+type Line struct {
+	Key  string
+	Text string
+}
+
+// indexLines indexes every line of the reader by the column at index column, or by the whole line if column < 0.
+// The lines without that column are skipped. It returns the trie and the number of lines indexed.
+func indexLines(reader io.Reader, column int, separator string) (*trie.Trie[[]Line], int, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	linesTrie := trie.New[[]Line]()
+	count := 0
+
+	for scanner.Scan() {
+		text := scanner.Text()
+		key := text
+
+		if column >= 0 {
+			columns := strings.Split(text, separator)
+			if column >= len(columns) {
+				continue
+			}
+
+			key = columns[column]
+		}
+
+		if len(key) == 0 {
+			continue
+		}
+
+		lines := []Line{{Key: key, Text: text}}
+		linesTrie.Insert(key, &lines, trie.Append[Line])
+		count++
+	}
+
+	if scanner.Err() != nil {
+		return nil, 0, scanner.Err()
+	}
+
+	return linesTrie, count, nil
+}
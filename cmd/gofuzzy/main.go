@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	fileName := flag.String("file", "-", "file to index, - for stdin")
+	column := flag.Int("column", -1, "index of the column to index, -1 for the whole line")
+	separator := flag.String("separator", "\t", "column separator")
+	distance := flag.Int("distance", 1, "max edit distance")
+	maxResults := flag.Int("n", 10, "max number of results per query")
+	prefix := flag.Bool("prefix", false, "match the lines starting with the query")
+	color := flag.String("color", "auto", "highlight the edits: auto, always or never")
+	flag.Parse()
+
+	queries := flag.Args()
+
+	var input io.Reader = os.Stdin
+	if *fileName != "-" {
+		file, err := os.Open(*fileName)
+		if err != nil {
+			fmt.Printf("failed to open file with error: %s\n", err.Error())
+			os.Exit(1)
+		}
+		defer file.Close()
+		input = file
+	} else if len(queries) == 0 {
+		println("queries have to be given as arguments when reading from stdin")
+		os.Exit(1)
+	}
+
+	useColors, err := colorsEnabled(*color)
+	if err != nil {
+		println(err.Error())
+		os.Exit(1)
+	}
+
+	linesTrie, _, err := indexLines(input, *column, *separator)
+	if err != nil {
+		fmt.Printf("failed to read input with error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	printer := &matchPrinter{
+		output:     bufio.NewWriter(os.Stdout),
+		trie:       linesTrie,
+		distance:   *distance,
+		maxResults: *maxResults,
+		prefix:     *prefix,
+		colors:     useColors,
+		showKeys:   *column >= 0,
+	}
+
+	if len(queries) > 0 {
+		for _, query := range queries {
+			printer.print(query)
+		}
+		printer.output.Flush()
+		return
+	}
+
+	// interactive mode, one query per line
+	scanner := bufio.NewScanner(os.Stdin)
+	print("> ")
+	for scanner.Scan() {
+		printer.print(scanner.Text())
+		printer.output.Flush()
+		print("> ")
+	}
+	println()
+}
+
+// colorsEnabled tells if the edits should be highlighted, in auto mode they are when stdout is a terminal.
+func colorsEnabled(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		info, err := os.Stdout.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+
+	return false, fmt.Errorf("unknown color mode '%s'", mode)
+}
+
+type matchPrinter struct {
+	output     *bufio.Writer
+	trie       *trie.Trie[[]Line]
+	distance   int
+	maxResults int
+	prefix     bool
+	colors     bool
+	// showKeys prints the key before the line, when only a column is indexed
+	showKeys bool
+}
+
+// print the matches of the query, one line per match: the distance, the key if needed, and the line.
+func (mp *matchPrinter) print(query string) {
+	collector := fuzzy.NewListCollector[[]Line](mp.maxResults)
+	fuzzy.SearchWithOptions[[]Line](context.Background(), mp.trie, query, mp.distance, collector, fuzzy.Options[[]Line]{
+		Prefix: mp.prefix,
+	})
+
+	queryRunes := []rune(query)
+	for _, result := range collector.Results {
+		for _, line := range *result.Value {
+			key := line.Key
+			if mp.colors {
+				keyRunes := []rune(key)
+				key = highlight(keyRunes, editedRunes(queryRunes, keyRunes, mp.prefix))
+			}
+
+			fields := []string{fmt.Sprint(result.Distance), key}
+			if mp.showKeys {
+				fields = append(fields, line.Text)
+			}
+
+			_, _ = mp.output.WriteString(strings.Join(fields, "\t") + "\n")
+		}
+	}
+}
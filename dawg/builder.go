@@ -0,0 +1,196 @@
+package dawg
+
+import (
+	"github.com/marcadamsge/gofuzzy/trie"
+	"unicode/utf8"
+)
+
+// Builder constructs a minimal DAWG from keys added in sorted order, the states are minimized as soon as no more
+// keys can reach them, so the whole trie of the keys never needs to be in memory.
+type Builder struct {
+	root *buildState
+	// unchecked holds the states of the last key added, they may still get new children so they're not minimized yet
+	unchecked []uncheckedState
+	// register maps the signature of the minimized states to the states
+	register map[string]*buildState
+	// registered holds the minimized states, a state is registered after all the states it leads to
+	registered []*buildState
+	signature  []byte
+	lastKey    string
+	keys       int
+}
+
+type buildState struct {
+	// id is the index of the state in the DAWG, it's set once the state is minimized
+	id    uint32
+	final bool
+	edges []buildEdge
+}
+
+type buildEdge struct {
+	r      rune
+	target *buildState
+}
+
+// uncheckedState is a state of the last key, reached with the last edge of parent.
+type uncheckedState struct {
+	parent *buildState
+	child  *buildState
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		root:     &buildState{},
+		register: make(map[string]*buildState),
+	}
+}
+
+// Add the key to the set. The keys must be added in increasing order (the order of the < operator on strings),
+// otherwise trie.ErrNotSorted is returned and the key is not added. A key added twice is only added once.
+func (builder *Builder) Add(key string) error {
+	if key < builder.lastKey {
+		return trie.ErrNotSorted
+	}
+
+	if key == builder.lastKey && builder.keys > 0 {
+		return nil
+	}
+
+	// the states of the prefix shared with the last key may still change, the ones past it can be minimized
+	suffix := key
+	lastSuffix := builder.lastKey
+	shared := 0
+	for len(suffix) > 0 && len(lastSuffix) > 0 {
+		r, size := utf8.DecodeRuneInString(suffix)
+		lastR, lastSize := utf8.DecodeRuneInString(lastSuffix)
+		if r != lastR {
+			break
+		}
+
+		suffix = suffix[size:]
+		lastSuffix = lastSuffix[lastSize:]
+		shared++
+	}
+
+	builder.minimize(shared)
+
+	crtState := builder.root
+	if len(builder.unchecked) > 0 {
+		crtState = builder.unchecked[len(builder.unchecked)-1].child
+	}
+
+	for _, r := range suffix {
+		child := &buildState{}
+		crtState.edges = append(crtState.edges, buildEdge{r: r, target: child})
+		builder.unchecked = append(builder.unchecked, uncheckedState{parent: crtState, child: child})
+		crtState = child
+	}
+
+	crtState.final = true
+	builder.lastKey = key
+	builder.keys++
+
+	return nil
+}
+
+// minimize the unchecked states past length: every state is replaced with an equivalent registered state if there is
+// one, or registered otherwise.
+func (builder *Builder) minimize(length int) {
+	for len(builder.unchecked) > length {
+		last := builder.unchecked[len(builder.unchecked)-1]
+		builder.unchecked = builder.unchecked[:len(builder.unchecked)-1]
+
+		signature := builder.signatureOf(last.child)
+		if equivalent, ok := builder.register[string(signature)]; ok {
+			last.parent.edges[len(last.parent.edges)-1].target = equivalent
+			continue
+		}
+
+		builder.registered = append(builder.registered, last.child)
+		last.child.id = uint32(len(builder.registered))
+		builder.register[string(signature)] = last.child
+	}
+}
+
+// signatureOf returns a string identifying the keys accepted from the state, the states it leads to must already be
+// minimized. The returned slice is only valid until the next call.
+func (builder *Builder) signatureOf(s *buildState) []byte {
+	signature := builder.signature[:0]
+	if s.final {
+		signature = append(signature, 1)
+	} else {
+		signature = append(signature, 0)
+	}
+
+	for _, e := range s.edges {
+		signature = appendUint32(signature, uint32(e.r))
+		signature = appendUint32(signature, e.target.id)
+	}
+
+	builder.signature = signature
+	return signature
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// Build returns the DAWG of all the keys added. The builder is reset and can be used to build another DAWG.
+func (builder *Builder) Build() *DAWG {
+	builder.minimize(0)
+
+	builder.registered = append(builder.registered, builder.root)
+	builder.root.id = uint32(len(builder.registered))
+
+	edgeCount := 0
+	for _, s := range builder.registered {
+		edgeCount += len(s.edges)
+	}
+
+	out := &DAWG{
+		states: make([]state, len(builder.registered)+1),
+		edges:  make([]edge, 0, edgeCount),
+		root:   builder.root.id,
+	}
+
+	// the states a state leads to are registered before it, so their count is already known
+	for _, s := range builder.registered {
+		count := uint32(0)
+		if s.final {
+			count = 1
+		}
+
+		firstEdge := uint32(len(out.edges))
+		for _, e := range s.edges {
+			out.edges = append(out.edges, edge{r: e.r, target: e.target.id, before: count})
+			count += out.states[e.target.id].count
+		}
+
+		out.states[s.id] = state{
+			firstEdge: firstEdge,
+			edgeCount: uint32(len(s.edges)),
+			count:     count,
+			final:     s.final,
+		}
+	}
+
+	*builder = *NewBuilder()
+
+	return out
+}
+
+// FromTrie builds the DAWG of the keys of the trie that have a value. It also returns the values sorted by key, so
+// that values[rank] is the value of the key with that rank.
+func FromTrie[T any](t *trie.Trie[T]) (*DAWG, []*T) {
+	builder := NewBuilder()
+	var values []*T
+
+	t.WalkSorted(func(key string, value *T) bool {
+		// the keys are visited in sorted order, so this can't fail
+		_ = builder.Add(key)
+		values = append(values, value)
+		return true
+	})
+
+	return builder.Build(), values
+}
@@ -0,0 +1,148 @@
+package dawg
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// DAWG is a minimal acyclic automaton holding a set of keys. Contrary to a trie, the keys sharing a suffix also share
+// the states of the suffix, which makes it a lot smaller than a trie for sets like city or street names.
+// A DAWG does not hold values, instead every key has a rank: its index in the sorted keys. The rank is computed
+// while stepping through the automaton, so the values of the keys found by a fuzzy search can be read from a side
+// array.
+type DAWG struct {
+	// states[0] is never used, this way the zero value of Node means there's no node
+	states []state
+	edges  []edge
+	root   uint32
+}
+
+type state struct {
+	// the edges leaving the state are edges[firstEdge:firstEdge+edgeCount], sorted by rune
+	firstEdge uint32
+	edgeCount uint32
+	// count is the number of keys accepted from this state
+	count uint32
+	final bool
+}
+
+type edge struct {
+	r      rune
+	target uint32
+	// before is the number of keys accepted from the source state that come before the ones of this edge
+	before uint32
+}
+
+// Len returns the number of keys.
+func (dawg *DAWG) Len() int {
+	return int(dawg.states[dawg.root].count)
+}
+
+// NumStates returns the number of states of the automaton, the equivalent of the number of nodes of a trie.
+func (dawg *DAWG) NumStates() int {
+	return len(dawg.states) - 1
+}
+
+// Bytes returns the memory used by the states and edges of the automaton, to compare it with trie.Stats.
+func (dawg *DAWG) Bytes() int {
+	return len(dawg.states)*int(unsafe.Sizeof(state{})) + len(dawg.edges)*int(unsafe.Sizeof(edge{}))
+}
+
+// Root returns the initial state of the automaton, it can be given to fuzzy.SearchNode.
+func (dawg *DAWG) Root() Node {
+	return Node{dawg: dawg, state: dawg.root}
+}
+
+// Contains tells if the key is in the set.
+func (dawg *DAWG) Contains(key string) bool {
+	_, ok := dawg.Index(key)
+	return ok
+}
+
+// Index returns the rank of the key, or false if the key is not in the set.
+func (dawg *DAWG) Index(key string) (int, bool) {
+	node := dawg.Root()
+	for _, r := range key {
+		node = node.Step(r)
+		if node.dawg == nil {
+			return 0, false
+		}
+	}
+
+	return node.Rank()
+}
+
+// Key returns the key with the given rank, or false if the rank is out of range.
+func (dawg *DAWG) Key(rank int) (string, bool) {
+	if rank < 0 || rank >= dawg.Len() {
+		return "", false
+	}
+
+	var runes []rune
+	remaining := uint32(rank)
+	crtState := dawg.root
+
+	for {
+		s := dawg.states[crtState]
+		if s.final && remaining == 0 {
+			return string(runes), true
+		}
+
+		// the key is reached with the last edge having fewer keys before it than remaining
+		edges := dawg.edges[s.firstEdge : s.firstEdge+s.edgeCount]
+		i := sort.Search(len(edges), func(i int) bool {
+			return edges[i].before > remaining
+		}) - 1
+
+		remaining -= edges[i].before
+		runes = append(runes, edges[i].r)
+		crtState = edges[i].target
+	}
+}
+
+// Node is a state of the DAWG reached with some prefix. It implements fuzzy.Node[Node, int], the value of a node
+// being the rank of its key.
+type Node struct {
+	dawg  *DAWG
+	state uint32
+	// rank is the number of keys before the ones starting with the prefix of the node
+	rank uint32
+}
+
+// Step out with the rune r and return the next node, or the zero Node if there is none.
+func (node Node) Step(r rune) Node {
+	s := node.dawg.states[node.state]
+	edges := node.dawg.edges[s.firstEdge : s.firstEdge+s.edgeCount]
+
+	i := sort.Search(len(edges), func(i int) bool {
+		return edges[i].r >= r
+	})
+	if i == len(edges) || edges[i].r != r {
+		return Node{}
+	}
+
+	return Node{dawg: node.dawg, state: edges[i].target, rank: node.rank + edges[i].before}
+}
+
+// Iterate over all the children of this node, by increasing rune.
+func (node Node) Iterate(iterationFunction func(r rune, node Node)) {
+	s := node.dawg.states[node.state]
+	for _, e := range node.dawg.edges[s.firstEdge : s.firstEdge+s.edgeCount] {
+		iterationFunction(e.r, Node{dawg: node.dawg, state: e.target, rank: node.rank + e.before})
+	}
+}
+
+// GetValue returns the rank of the key of this node, or nil if there is no key ending here.
+func (node Node) GetValue() *int {
+	rank, ok := node.Rank()
+	if !ok {
+		return nil
+	}
+
+	return &rank
+}
+
+// Rank returns the rank of the key of this node, or false if there is no key ending here.
+func (node Node) Rank() (int, bool) {
+	return int(node.rank), node.dawg.states[node.state].final
+}
@@ -0,0 +1,146 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var testKeys = []string{"", "talk", "talked", "talking", "walk", "walked", "walking", "wall", "⌘", "⌘alk"}
+
+func TestDAWG(t *testing.T) {
+	builder := NewBuilder()
+	for _, key := range testKeys {
+		if err := builder.Add(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// duplicates are ignored
+	if err := builder.Add(testKeys[len(testKeys)-1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builder.Add("a"); !errors.Is(err, trie.ErrNotSorted) {
+		t.Fatalf("expected ErrNotSorted but got %v", err)
+	}
+
+	dawg := builder.Build()
+
+	if dawg.Len() != len(testKeys) {
+		t.Fatalf("expected %d keys but got %d", len(testKeys), dawg.Len())
+	}
+
+	for i, key := range testKeys {
+		rank, ok := dawg.Index(key)
+		if !ok || rank != i {
+			t.Fatalf("unexpected rank %d for %s", rank, key)
+		}
+
+		if k, ok := dawg.Key(i); !ok || k != key {
+			t.Fatalf("unexpected key %s for rank %d", k, i)
+		}
+	}
+
+	for _, key := range []string{"tal", "walke", "walkings", "a", "⌘a"} {
+		if dawg.Contains(key) {
+			t.Fatalf("%s should not be in the set", key)
+		}
+	}
+
+	if _, ok := dawg.Key(len(testKeys)); ok {
+		t.Fatal("the rank should be out of range")
+	}
+
+	// talk and walk share the states of their 'ed' and 'ing' suffixes
+	if dawg.NumStates() != 15 {
+		t.Fatalf("unexpected number of states %d", dawg.NumStates())
+	}
+
+	if dawg.Bytes() <= 0 {
+		t.Fatal("the DAWG should use some memory")
+	}
+
+	if empty := builder.Build(); empty.Len() != 0 || empty.Contains("") {
+		t.Fatal("the builder should of been reset")
+	}
+}
+
+func TestFromTrie(t *testing.T) {
+	testTrie := trie.New[string]()
+	for i := len(testKeys) - 1; i >= 0; i-- {
+		value := strings.ToUpper(testKeys[i])
+		testTrie.Insert(testKeys[i], &value, trie.KeepFirst[string])
+	}
+
+	dawg, values := FromTrie[string](testTrie)
+	if dawg.Len() != len(testKeys) || len(values) != len(testKeys) {
+		t.Fatalf("unexpected length %d", dawg.Len())
+	}
+
+	for i, key := range testKeys {
+		rank, ok := dawg.Index(key)
+		if !ok || rank != i || *values[rank] != strings.ToUpper(key) {
+			t.Fatalf("unexpected value for %s", key)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	words := []string{"", "cat", "tat", "dog", "cart", "card", "bat", "at", "catalog", "dot", "⌘at", "scat", "bart"}
+	sort.Strings(words)
+
+	testTrie := trie.New[string]()
+	builder := NewBuilder()
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepFirst[string])
+		if err := builder.Add(words[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dawg := builder.Build()
+
+	for _, query := range []string{"cat", "at", "", "dgo", "catalgo", "xyz", "bt"} {
+		for distance := 0; distance <= 3; distance++ {
+			for _, options := range []fuzzy.Options[int]{{}, {Workers: 3}, {Prefix: true}} {
+				expected := fuzzy.NewListCollector[string](-1)
+				_, err := fuzzy.SearchWithOptions[string](context.Background(), testTrie, query, distance, expected, fuzzy.Options[string]{
+					Workers: options.Workers,
+					Prefix:  options.Prefix,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual := fuzzy.NewListCollector[int](-1)
+				_, err = fuzzy.SearchNode[Node, int](context.Background(), dawg.Root(), query, distance, actual, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var expectedResults []string
+				for _, result := range expected.Results {
+					expectedResults = append(expectedResults, fmt.Sprintf("%d:%s", result.Distance, *result.Value))
+				}
+
+				var actualResults []string
+				for _, result := range actual.Results {
+					actualResults = append(actualResults, fmt.Sprintf("%d:%s", result.Distance, words[*result.Value]))
+				}
+
+				// the results at the same distance may come in any order
+				sort.Strings(expectedResults)
+				sort.Strings(actualResults)
+				if strings.Join(expectedResults, "|") != strings.Join(actualResults, "|") {
+					t.Fatalf("search %s with distance %d and options %+v: expected %v but got %v",
+						query, distance, options, expectedResults, actualResults)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package distance computes the edit distances between two strings, rune by rune:
+//   - Levenshtein counts the insertions, deletions and substitutions
+//   - OSA, the optimal string alignment distance, also counts the swaps of two adjacent runes as one edit, as long as
+//     no substring is edited more than once. It's the distance reported by fuzzy.Search
+//   - DamerauLevenshtein is the true Damerau-Levenshtein distance, where swapped runes can be edited again, so that
+//     DamerauLevenshtein("CA", "ABC") is 2 while OSA("CA", "ABC") is 3
+package distance
+
+// Levenshtein returns the Levenshtein distance between a and b.
+func Levenshtein(a string, b string) int {
+	return new(Calculator).Levenshtein(a, b, -1)
+}
+
+// OSA returns the optimal string alignment distance between a and b.
+func OSA(a string, b string) int {
+	return new(Calculator).OSA(a, b, -1)
+}
+
+// DamerauLevenshtein returns the Damerau-Levenshtein distance between a and b.
+func DamerauLevenshtein(a string, b string) int {
+	return new(Calculator).DamerauLevenshtein(a, b, -1)
+}
+
+// Calculator computes bounded distances, and keeps its buffers from one call to the next so that it stops allocating
+// once they're large enough for the strings compared.
+// If max >= 0, the methods stop as soon as the distance is known to be larger than max and return max+1.
+// A Calculator is not safe for concurrent use.
+type Calculator struct {
+	a, b []rune
+	rows [3][]int
+	// matrix and lastRows are only used by DamerauLevenshtein
+	matrix   []int
+	lastRows map[rune]int
+}
+
+// Levenshtein returns the Levenshtein distance between a and b, bounded by max.
+func (c *Calculator) Levenshtein(a string, b string, max int) int {
+	ra, rb, ok := c.decode(a, b, max)
+	if !ok {
+		return max + 1
+	}
+
+	previous, current := c.row(0, len(rb)+1), c.row(1, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := i
+
+		for j := 1; j <= len(rb); j++ {
+			current[j] = minOf(previous[j]+1, current[j-1]+1, previous[j-1]+cost(ra[i-1], rb[j-1]))
+			if current[j] < rowMin {
+				rowMin = current[j]
+			}
+		}
+
+		// the smallest value of a row never decreases from one row to the next
+		if max >= 0 && rowMin > max {
+			return max + 1
+		}
+
+		previous, current = current, previous
+	}
+
+	return bound(previous[len(rb)], max)
+}
+
+// OSA returns the optimal string alignment distance between a and b, bounded by max.
+func (c *Calculator) OSA(a string, b string, max int) int {
+	ra, rb, ok := c.decode(a, b, max)
+	if !ok {
+		return max + 1
+	}
+
+	beforePrevious, previous, current := c.row(0, len(rb)+1), c.row(1, len(rb)+1), c.row(2, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := i
+
+		for j := 1; j <= len(rb); j++ {
+			current[j] = minOf(previous[j]+1, current[j-1]+1, previous[j-1]+cost(ra[i-1], rb[j-1]))
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && beforePrevious[j-2]+1 < current[j] {
+				current[j] = beforePrevious[j-2] + 1
+			}
+
+			if current[j] < rowMin {
+				rowMin = current[j]
+			}
+		}
+
+		if max >= 0 && rowMin > max {
+			return max + 1
+		}
+
+		beforePrevious, previous, current = previous, current, beforePrevious
+	}
+
+	return bound(previous[len(rb)], max)
+}
+
+// DamerauLevenshtein returns the Damerau-Levenshtein distance between a and b, bounded by max.
+// It's the algorithm of Lowrance and Wagner, which needs the whole matrix instead of the last rows.
+func (c *Calculator) DamerauLevenshtein(a string, b string, max int) int {
+	ra, rb, ok := c.decode(a, b, max)
+	if !ok {
+		return max + 1
+	}
+
+	// the matrix has a border of one row and one column holding a value larger than any distance, so it's indexed
+	// from -1 and d(i, j) is at matrix[(i+1)*width+j+1]
+	width := len(rb) + 2
+	size := (len(ra) + 2) * width
+	if cap(c.matrix) < size {
+		c.matrix = make([]int, size)
+	}
+	matrix := c.matrix[:size]
+	at := func(i int, j int) int {
+		return (i+1)*width + j + 1
+	}
+
+	infinity := len(ra) + len(rb)
+	matrix[at(-1, -1)] = infinity
+	for i := 0; i <= len(ra); i++ {
+		matrix[at(i, -1)] = infinity
+		matrix[at(i, 0)] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		matrix[at(-1, j)] = infinity
+		matrix[at(0, j)] = j
+	}
+
+	// lastRows holds the last row where every rune of a was seen
+	if c.lastRows == nil {
+		c.lastRows = make(map[rune]int)
+	}
+	for r := range c.lastRows {
+		delete(c.lastRows, r)
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		// lastColumn is the last column of the row where the runes matched
+		lastColumn := 0
+		rowMin := i
+
+		for j := 1; j <= len(rb); j++ {
+			k := c.lastRows[rb[j-1]]
+			l := lastColumn
+
+			substitution := 1
+			if ra[i-1] == rb[j-1] {
+				substitution = 0
+				lastColumn = j
+			}
+
+			value := minOf(
+				matrix[at(i-1, j-1)]+substitution,
+				matrix[at(i, j-1)]+1,
+				matrix[at(i-1, j)]+1,
+			)
+
+			// the runes between the swapped ones are deleted or inserted
+			if swap := matrix[at(k-1, l-1)] + (i - k - 1) + 1 + (j - l - 1); swap < value {
+				value = swap
+			}
+
+			matrix[at(i, j)] = value
+			if value < rowMin {
+				rowMin = value
+			}
+		}
+
+		if max >= 0 && rowMin > max {
+			return max + 1
+		}
+
+		c.lastRows[ra[i-1]] = i
+	}
+
+	return bound(matrix[at(len(ra), len(rb))], max)
+}
+
+// decode returns the runes of a and b in the buffers of the calculator. ok is false if the difference of length of
+// the strings is already larger than max.
+func (c *Calculator) decode(a string, b string, max int) (ra []rune, rb []rune, ok bool) {
+	c.a = appendRunes(c.a[:0], a)
+	c.b = appendRunes(c.b[:0], b)
+
+	difference := len(c.a) - len(c.b)
+	if difference < 0 {
+		difference = -difference
+	}
+
+	return c.a, c.b, max < 0 || difference <= max
+}
+
+// row returns the buffer of the i-th row with the given length.
+func (c *Calculator) row(i int, length int) []int {
+	if cap(c.rows[i]) < length {
+		c.rows[i] = make([]int, length)
+	}
+
+	return c.rows[i][:length]
+}
+
+func appendRunes(out []rune, str string) []rune {
+	for _, r := range str {
+		out = append(out, r)
+	}
+
+	return out
+}
+
+func cost(r1 rune, r2 rune) int {
+	if r1 == r2 {
+		return 0
+	}
+
+	return 1
+}
+
+func bound(distance int, max int) int {
+	if max >= 0 && distance > max {
+		return max + 1
+	}
+
+	return distance
+}
+
+func minOf(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+
+	if c < a {
+		a = c
+	}
+
+	return a
+}
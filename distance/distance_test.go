@@ -0,0 +1,109 @@
+package distance
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestKnownDistances(t *testing.T) {
+	tests := []struct {
+		a, b                      string
+		levenshtein, osa, damerau int
+	}{
+		{"", "", 0, 0, 0},
+		{"", "abc", 3, 3, 3},
+		{"kitten", "sitting", 3, 3, 3},
+		{"bern", "bren", 2, 1, 1},
+		{"CA", "ABC", 3, 3, 2},
+		{"zürich", "zurich", 1, 1, 1},
+		{"abcdef", "badcfe", 4, 3, 3},
+		{"Saint-Étienne", "St Etienne", 5, 5, 5},
+	}
+
+	for _, test := range tests {
+		if d := Levenshtein(test.a, test.b); d != test.levenshtein {
+			t.Errorf("Levenshtein(%s, %s) = %d, expected %d", test.a, test.b, d, test.levenshtein)
+		}
+
+		if d := OSA(test.a, test.b); d != test.osa {
+			t.Errorf("OSA(%s, %s) = %d, expected %d", test.a, test.b, d, test.osa)
+		}
+
+		if d := DamerauLevenshtein(test.a, test.b); d != test.damerau {
+			t.Errorf("DamerauLevenshtein(%s, %s) = %d, expected %d", test.a, test.b, d, test.damerau)
+		}
+	}
+}
+
+func randomString(random *rand.Rand, alphabet []rune, maxLength int) string {
+	runes := make([]rune, random.Intn(maxLength+1))
+	for i := range runes {
+		runes[i] = alphabet[random.Intn(len(alphabet))]
+	}
+
+	return string(runes)
+}
+
+func TestDistanceProperties(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	alphabet := []rune("abcé")
+	calculator := &Calculator{}
+
+	for n := 0; n < 2000; n++ {
+		a, b, c := randomString(random, alphabet, 8), randomString(random, alphabet, 8), randomString(random, alphabet, 8)
+
+		levenshtein, osa, damerau := Levenshtein(a, b), OSA(a, b), DamerauLevenshtein(a, b)
+		if damerau > osa || osa > levenshtein {
+			t.Fatalf("expected %d <= %d <= %d for '%s' and '%s'", damerau, osa, levenshtein, a, b)
+		}
+
+		if levenshtein != Levenshtein(b, a) || osa != OSA(b, a) || damerau != DamerauLevenshtein(b, a) {
+			t.Fatalf("the distances between '%s' and '%s' should be symmetric", a, b)
+		}
+
+		// OSA does not respect the triangle inequality
+		if levenshtein > Levenshtein(a, c)+Levenshtein(c, b) {
+			t.Fatalf("Levenshtein breaks the triangle inequality with '%s', '%s' and '%s'", a, b, c)
+		}
+		if damerau > DamerauLevenshtein(a, c)+DamerauLevenshtein(c, b) {
+			t.Fatalf("DamerauLevenshtein breaks the triangle inequality with '%s', '%s' and '%s'", a, b, c)
+		}
+
+		for max := 0; max <= 4; max++ {
+			if d := calculator.Levenshtein(a, b, max); d != bound(levenshtein, max) {
+				t.Fatalf("Levenshtein('%s', '%s', %d) = %d, expected %d", a, b, max, d, bound(levenshtein, max))
+			}
+			if d := calculator.OSA(a, b, max); d != bound(osa, max) {
+				t.Fatalf("OSA('%s', '%s', %d) = %d, expected %d", a, b, max, d, bound(osa, max))
+			}
+			if d := calculator.DamerauLevenshtein(a, b, max); d != bound(damerau, max) {
+				t.Fatalf("DamerauLevenshtein('%s', '%s', %d) = %d, expected %d", a, b, max, d, bound(damerau, max))
+			}
+		}
+	}
+}
+
+func TestCalculatorDoesNotAllocate(t *testing.T) {
+	calculator := &Calculator{}
+	// the buffers are allocated by the first calls
+	calculator.Levenshtein("kitten", "sitting", -1)
+	calculator.OSA("kitten", "sitting", -1)
+	calculator.DamerauLevenshtein("kitten", "sitting", -1)
+
+	allocations := testing.AllocsPerRun(100, func() {
+		calculator.Levenshtein("sitting", "kitten", -1)
+		calculator.OSA("sitting", "kitten", 2)
+		calculator.DamerauLevenshtein("sitting", "kitten", -1)
+	})
+
+	if allocations != 0 {
+		t.Fatalf("expected no allocation, got %f", allocations)
+	}
+}
+
+func BenchmarkOSA(b *testing.B) {
+	calculator := &Calculator{}
+	for i := 0; i < b.N; i++ {
+		calculator.OSA("Saint-Étienne", "St Etienne", 2)
+	}
+}
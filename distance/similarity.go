@@ -0,0 +1,127 @@
+package distance
+
+// JaroWinkler returns the Jaro-Winkler similarity between a and b, from 0 for nothing in common to 1 for equal
+// strings. It favours the strings sharing a prefix of up to 4 runes, with the usual scaling factor of 0.1.
+func JaroWinkler(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	// two runes match if they're equal and not further apart than the window
+	window := len(ra)
+	if len(rb) > window {
+		window = len(rb)
+	}
+	window = window/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+
+	for i, r := range ra {
+		start := i - window
+		if start < 0 {
+			start = 0
+		}
+
+		end := i + window + 1
+		if end > len(rb) {
+			end = len(rb)
+		}
+
+		for j := start; j < end; j++ {
+			if !matchedB[j] && rb[j] == r {
+				matchedA[i] = true
+				matchedB[j] = true
+				matches++
+				break
+			}
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	// half of the matched runes that are not in the same order
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+
+		for !matchedB[j] {
+			j++
+		}
+
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := commonPrefix(ra, rb)
+	if prefix > 4 {
+		prefix = 4
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// CommonPrefix returns the number of runes a and b start with.
+func CommonPrefix(a string, b string) int {
+	return commonPrefix([]rune(a), []rune(b))
+}
+
+func commonPrefix(ra []rune, rb []rune) int {
+	length := 0
+	for length < len(ra) && length < len(rb) && ra[length] == rb[length] {
+		length++
+	}
+
+	return length
+}
+
+// BigramDice returns the Dice coefficient of the bigrams of a and b: twice the number of bigrams they share over the
+// total number of bigrams, from 0 to 1. The strings shorter than 2 runes have no bigram, so their similarity is 1 if
+// they're equal and 0 otherwise.
+func BigramDice(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < 2 || len(rb) < 2 {
+		if a == b {
+			return 1
+		}
+
+		return 0
+	}
+
+	type bigram [2]rune
+
+	bigramsA := make(map[bigram]int, len(ra)-1)
+	for i := 1; i < len(ra); i++ {
+		bigramsA[bigram{ra[i-1], ra[i]}]++
+	}
+
+	shared := 0
+	for i := 1; i < len(rb); i++ {
+		key := bigram{rb[i-1], rb[i]}
+		if bigramsA[key] > 0 {
+			bigramsA[key]--
+			shared++
+		}
+	}
+
+	return 2 * float64(shared) / float64(len(ra)-1+len(rb)-1)
+}
@@ -0,0 +1,43 @@
+package distance
+
+import (
+	"math"
+	"testing"
+)
+
+func checkSimilarity(t *testing.T, name string, similarity func(a string, b string) float64, a string, b string, expected float64) {
+	t.Helper()
+
+	if s := similarity(a, b); math.Abs(s-expected) > 0.001 {
+		t.Errorf("%s(%s, %s) = %f, expected %f", name, a, b, s, expected)
+	}
+
+	if similarity(a, b) != similarity(b, a) {
+		t.Errorf("%s(%s, %s) should be symmetric", name, a, b)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	checkSimilarity(t, "JaroWinkler", JaroWinkler, "MARTHA", "MARHTA", 0.961)
+	checkSimilarity(t, "JaroWinkler", JaroWinkler, "DWAYNE", "DUANE", 0.84)
+	checkSimilarity(t, "JaroWinkler", JaroWinkler, "DIXON", "DICKSONX", 0.813)
+	checkSimilarity(t, "JaroWinkler", JaroWinkler, "zürich", "zürich", 1)
+	checkSimilarity(t, "JaroWinkler", JaroWinkler, "abc", "xyz", 0)
+	checkSimilarity(t, "JaroWinkler", JaroWinkler, "", "", 1)
+	checkSimilarity(t, "JaroWinkler", JaroWinkler, "", "a", 0)
+}
+
+func TestBigramDice(t *testing.T) {
+	checkSimilarity(t, "BigramDice", BigramDice, "night", "nacht", 0.25)
+	checkSimilarity(t, "BigramDice", BigramDice, "bern", "berne", 6.0/7)
+	// the repeated bigrams are counted as many times as they're shared
+	checkSimilarity(t, "BigramDice", BigramDice, "aaaa", "aa", 0.5)
+	checkSimilarity(t, "BigramDice", BigramDice, "a", "a", 1)
+	checkSimilarity(t, "BigramDice", BigramDice, "a", "b", 0)
+}
+
+func TestCommonPrefix(t *testing.T) {
+	if CommonPrefix("zürich", "zürs") != 3 || CommonPrefix("bern", "fern") != 0 || CommonPrefix("", "bern") != 0 {
+		t.Fatal("unexpected common prefix")
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// haversineKm returns the great-circle distance between two locations in kilometers.
+func haversineKm(l1 *GeoLocation, l2 *GeoLocation) float64 {
+	lat1 := toRadians(float64(l1.Latitude))
+	lat2 := toRadians(float64(l2.Latitude))
+	deltaLat := lat2 - lat1
+	deltaLon := toRadians(float64(l2.Longitude) - float64(l1.Longitude))
+
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+)
+
+// CityQuery describes a city lookup by name.
+type CityQuery struct {
+	Name        string
+	MaxDistance int
+	MaxResults  int
+	// Reference is optional, when set the cities at the same edit distance are ranked by how close they are to it
+	Reference *GeoLocation
+	// RadiusKm only keeps the cities within that radius around Reference, it's ignored if <= 0 or Reference is nil
+	RadiusKm float64
+}
+
+// CityMatch is a single location matching a CityQuery.
+type CityMatch struct {
+	// Name is the main name of the city
+	Name string
+	// Alias is the name of the city that matched the query, it's the same as Name if the main name matched
+	Alias    string
+	Location *GeoLocation
+	// Distance is the edit distance between the query and the name
+	Distance int
+	// DistanceKm is the distance to the reference point of the query, or 0 if there's none
+	DistanceKm float64
+}
+
+// lookupCities searches the cities by name. The closest names come first, and the cities with names at the same edit
+// distance are ordered by how close they are to the reference point.
+func lookupCities(ctx context.Context, geoNamesTrie *trie.Trie[Entry], query CityQuery) ([]CityMatch, error) {
+	withinRadius := func(location *GeoLocation) bool {
+		return query.Reference == nil || query.RadiusKm <= 0 || haversineKm(query.Reference, location) <= query.RadiusKm
+	}
+
+	options := fuzzy.Options[Entry]{}
+	if query.Reference != nil && query.RadiusKm > 0 {
+		options.Filter = func(entry *Entry) bool {
+			for location := range entry.LocationSet {
+				if withinRadius(location) {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	collector := newLevelCollector[Entry](query.MaxResults)
+	_, err := fuzzy.SearchWithOptions[Entry](ctx, geoNamesTrie, query.Name, query.MaxDistance, collector, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []CityMatch
+	// a city can match under several of its names, the results are ordered by distance so the first one is the best
+	seen := make(map[*GeoLocation]struct{})
+	for _, result := range collector.Results {
+		for location := range result.Value.LocationSet {
+			if _, ok := seen[location]; ok || !withinRadius(location) {
+				continue
+			}
+			seen[location] = struct{}{}
+
+			match := CityMatch{
+				Name:     location.Name,
+				Alias:    result.Value.Name,
+				Location: location,
+				Distance: result.Distance,
+			}
+
+			if query.Reference != nil {
+				match.DistanceKm = haversineKm(query.Reference, location)
+			}
+
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+
+		return matches[i].DistanceKm < matches[j].DistanceKm
+	})
+
+	if query.MaxResults >= 0 && len(matches) > query.MaxResults {
+		matches = matches[:query.MaxResults]
+	}
+
+	return matches, nil
+}
+
+// levelCollector collects results until it has at least maxResult of them, and then keeps collecting the results
+// at the same distance as the last one. This way the results can be re-ranked within the same distance.
+// If maxResult < 0, it collects forever.
+type levelCollector[T any] struct {
+	maxResult int
+	done      bool
+	Results   []fuzzy.Result[T]
+}
+
+func newLevelCollector[T any](maxResult int) *levelCollector[T] {
+	return &levelCollector[T]{
+		maxResult: maxResult,
+		Results:   make([]fuzzy.Result[T], 0),
+	}
+}
+
+func (lc *levelCollector[T]) Collect(t *T, distance int) {
+	if t == nil || lc.done {
+		return
+	}
+
+	if lc.maxResult >= 0 && len(lc.Results) >= lc.maxResult && distance > lc.Results[len(lc.Results)-1].Distance {
+		// the search moved on to the next distance, the results we have are complete
+		lc.done = true
+		return
+	}
+
+	lc.Results = append(lc.Results, fuzzy.Result[T]{
+		Value:    t,
+		Distance: distance,
+	})
+}
+
+func (lc *levelCollector[T]) Done() bool {
+	return lc.done || lc.maxResult == 0
+}
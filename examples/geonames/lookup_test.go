@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/cities.txt
+var citiesFixture string
+
+func loadFixture(t *testing.T) *trie.Trie[Entry] {
+	geoNamesTrie, _, err := parseGeoNamesFile(strings.NewReader(citiesFixture), true, false)
+	if err != nil {
+		t.Fatalf("failed to parse the fixture: %s", err.Error())
+	}
+
+	return geoNamesTrie
+}
+
+func TestHaversine(t *testing.T) {
+	bern := &GeoLocation{Latitude: 46.94809, Longitude: 7.44744}
+	paris := &GeoLocation{Latitude: 48.85341, Longitude: 2.3488}
+
+	if haversineKm(bern, bern) != 0 {
+		t.Fatal("the distance between a location and itself should be 0")
+	}
+
+	// the distance between Bern and Paris is about 435km
+	distance := haversineKm(bern, paris)
+	if math.Abs(distance-435) > 5 || distance != haversineKm(paris, bern) {
+		t.Fatalf("unexpected distance between Bern and Paris: %f", distance)
+	}
+}
+
+func TestLookupCities(t *testing.T) {
+	geoNamesTrie := loadFixture(t)
+
+	matches, err := lookupCities(context.Background(), geoNamesTrie, CityQuery{
+		Name:        "Paris",
+		MaxDistance: 1,
+		MaxResults:  1,
+		Reference:   &GeoLocation{Latitude: 32.7767, Longitude: -96.797},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both Paris are at distance 0, the one in Texas is closer to Dallas
+	if len(matches) != 1 || matches[0].Location.Country != "US" || matches[0].Distance != 0 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+
+	matches, err = lookupCities(context.Background(), geoNamesTrie, CityQuery{
+		Name:        "Paris",
+		MaxDistance: 1,
+		MaxResults:  2,
+		Reference:   &GeoLocation{Latitude: 48.8, Longitude: 2.3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 2 || matches[0].Location.Country != "FR" || matches[1].Location.Country != "US" {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+
+	if matches[0].DistanceKm > matches[1].DistanceKm {
+		t.Fatal("the matches should be ordered by distance to the reference point")
+	}
+}
+
+func TestLookupCitiesEditDistanceFirst(t *testing.T) {
+	geoNamesTrie := loadFixture(t)
+
+	// Berne in Indiana is the closest to the reference point, but Bern is a better match
+	matches, err := lookupCities(context.Background(), geoNamesTrie, CityQuery{
+		Name:        "Bern",
+		MaxDistance: 1,
+		MaxResults:  3,
+		Reference:   &GeoLocation{Latitude: 40.6, Longitude: -84.9},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 3 || matches[0].Name != "Bern" || matches[1].Name != "Bern" || matches[2].Name != "Berne" {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+
+	if matches[0].Location.Country != "US" {
+		t.Fatal("Bern in Kansas should come first, it's closer to the reference point")
+	}
+}
+
+func TestLookupCitiesWithinRadius(t *testing.T) {
+	geoNamesTrie := loadFixture(t)
+
+	matches, err := lookupCities(context.Background(), geoNamesTrie, CityQuery{
+		Name:        "Berlin",
+		MaxDistance: 2,
+		MaxResults:  5,
+		Reference:   &GeoLocation{Latitude: 47, Longitude: 8},
+		RadiusKm:    200,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// neither Berlin is within 200km, but Bern is at distance 2
+	if len(matches) != 1 || matches[0].Name != "Bern" || matches[0].Location.Country != "CH" || matches[0].Distance != 2 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+}
+
+func TestLookupCitiesWithoutReference(t *testing.T) {
+	geoNamesTrie := loadFixture(t)
+
+	matches, err := lookupCities(context.Background(), geoNamesTrie, CityQuery{
+		Name:        "Zurich",
+		MaxDistance: 1,
+		MaxResults:  5,
+		RadiusKm:    10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Zurich is the ASCII name of Zürich
+	if len(matches) != 1 || matches[0].Name != "Zürich" || matches[0].Alias != "Zurich" || matches[0].Distance != 0 || matches[0].DistanceKm != 0 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = lookupCities(ctx, geoNamesTrie, CityQuery{Name: "Zurich", MaxDistance: 1, MaxResults: 5})
+	if err == nil {
+		t.Fatal("a canceled lookup should return an error")
+	}
+}
+
+func TestLookupCitiesByAlias(t *testing.T) {
+	geoNamesTrie := loadFixture(t)
+
+	matches, err := lookupCities(context.Background(), geoNamesTrie, CityQuery{
+		Name:        "Munich",
+		MaxDistance: 0,
+		MaxResults:  5,
+		Reference:   &GeoLocation{Latitude: 48, Longitude: 11},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 2 {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+
+	if matches[0].Name != "München" || matches[0].Alias != "Munich" || matches[0].Location.Country != "DE" {
+		t.Fatalf("München should of been found by its alias, got %+v", matches[0])
+	}
+
+	if matches[1].Name != "Munich" || matches[1].Alias != "Munich" || matches[1].Location.Country != "US" {
+		t.Fatalf("Munich in North Dakota should of been found by its name, got %+v", matches[1])
+	}
+
+	// the main name is still indexed
+	matches, err = lookupCities(context.Background(), geoNamesTrie, CityQuery{Name: "München", MaxResults: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 1 || matches[0].Name != "München" || matches[0].Alias != "München" {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+}
+
+func TestCityAliases(t *testing.T) {
+	aliases := cityAliases("Zürich", "Zurich", "Zurich, Zurigo,,Zürich")
+
+	if strings.Join(aliases, "|") != "Zürich|Zurich|Zurigo" {
+		t.Fatalf("unexpected aliases %v", aliases)
+	}
+
+	if strings.Join(cityAliases("Bern", "Bern", ""), "|") != "Bern" {
+		t.Fatal("a city without aliases should only have its name")
+	}
+}
+
+func TestParseWithoutAliases(t *testing.T) {
+	geoNamesTrie, linesParsed, err := parseGeoNamesFile(strings.NewReader(citiesFixture), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the Swiss Alps are not a city
+	if linesParsed != 11 {
+		t.Fatalf("unexpected number of lines parsed %d", linesParsed)
+	}
+
+	matches, err := lookupCities(context.Background(), geoNamesTrie, CityQuery{Name: "Zurich", MaxResults: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 0 {
+		t.Fatal("the aliases should not of been indexed")
+	}
+}
+
+func TestParseBulk(t *testing.T) {
+	geoNamesTrie := loadFixture(t)
+	bulkTrie, _, err := parseGeoNamesFile(strings.NewReader(citiesFixture), true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Bern", "Zurich", "Pari", "Munchen"} {
+		query := CityQuery{Name: name, MaxDistance: 2, MaxResults: 100}
+
+		expected, err := lookupCities(context.Background(), geoNamesTrie, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		matches, err := lookupCities(context.Background(), bulkTrie, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the matches at the same distance may come in any order
+		if matchSet(matches) != matchSet(expected) {
+			t.Fatalf("expected %s for %s but got %s", matchSet(expected), name, matchSet(matches))
+		}
+	}
+}
+
+func matchSet(matches []CityMatch) string {
+	out := make([]string, 0, len(matches))
+	for _, match := range matches {
+		out = append(out, fmt.Sprintf("%s:%s:%d", match.Name, match.Location.Name, match.Distance))
+	}
+	sort.Strings(out)
+
+	return strings.Join(out, "|")
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"github.com/marcadamsge/gofuzzy/extract"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"math"
 	"os"
 	"runtime"
 	"time"
@@ -12,6 +16,14 @@ func main() {
 	geoNamesFileName := flag.String("geo", "", "geonames file to parse")
 	threads := flag.Int("threads", runtime.NumCPU(), "number of threads to use for the test")
 	maxResults := flag.Int("n", 1, "max number of results per test")
+	query := flag.String("query", "", "look up a city by name instead of running the performance test")
+	text := flag.String("text", "", "find the cities mentioned in a text instead of running the performance test")
+	maxDistance := flag.Int("distance", 1, "max edit distance of the city lookup")
+	latitude := flag.Float64("lat", math.NaN(), "latitude of the reference point of the city lookup")
+	longitude := flag.Float64("lon", math.NaN(), "longitude of the reference point of the city lookup")
+	radius := flag.Float64("radius", 0, "only look up cities within this radius in km around the reference point")
+	withAliases := flag.Bool("aliases", true, "also index the cities by their ASCII and alternate names")
+	bulk := flag.Bool("bulk", false, "sort the names and build the trie at once instead of inserting them one by one")
 	flag.Parse()
 
 	if geoNamesFileName == nil || *geoNamesFileName == "" {
@@ -38,12 +50,47 @@ func main() {
 	}
 	defer geoNamesReader.Close()
 
-	geoNamesTrie, numberOfLines, err := parseGeoNamesFile(geoNamesReader)
+	geoNamesTrie, numberOfLines, err := parseGeoNamesFile(geoNamesReader, *withAliases, *bulk)
 	if err != nil {
 		fmt.Printf("failed to read geonames file with error: %s\n", err.Error())
 		os.Exit(1)
 	}
 
+	if *text != "" {
+		err = printEntities(geoNamesTrie, *text)
+		if err != nil {
+			fmt.Printf("failed to extract the cities with error: %s\n", err.Error())
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if *query != "" {
+		cityQuery := CityQuery{
+			Name:        *query,
+			MaxDistance: *maxDistance,
+			MaxResults:  *maxResults,
+			RadiusKm:    *radius,
+		}
+
+		if !math.IsNaN(*latitude) && !math.IsNaN(*longitude) {
+			cityQuery.Reference = &GeoLocation{
+				Latitude:  float32(*latitude),
+				Longitude: float32(*longitude),
+			}
+		}
+
+		err = printCityLookup(geoNamesTrie, cityQuery)
+		if err != nil {
+			fmt.Printf("failed to look up '%s' with error: %s\n", *query, err.Error())
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	printTrieStats(geoNamesTrie)
 	triggerGC()
 
 	_, err = geoNamesReader.Seek(0, 0)
@@ -89,3 +136,58 @@ func triggerGC() {
 	runtime.ReadMemStats(&m)
 	fmt.Printf("Allocated Memory = %v MiB\n", m.Alloc/1024/1024)
 }
+
+func printTrieStats(geoNamesTrie *trie.Trie[Entry]) {
+	stats := geoNamesTrie.Stats()
+	fmt.Printf(
+		"%d nodes, %d values, max depth %d, estimated trie memory = %v MiB\n",
+		stats.Nodes,
+		stats.Values,
+		stats.MaxDepth,
+		stats.Bytes.Total()/1024/1024,
+	)
+}
+
+func printEntities(geoNamesTrie *trie.Trie[Entry], text string) error {
+	// city names rarely have more than 3 words, like Rio de Janeiro
+	spans, err := extract.Extract[Entry](context.Background(), geoNamesTrie, text, extract.Config{MaxTokens: 3})
+	if err != nil {
+		return err
+	}
+
+	for _, span := range spans {
+		fmt.Printf("'%s' at %d: %s, %d location(s), distance %d\n", span.Text, span.Start, span.Value.Name, len(span.Value.LocationSet), span.Distance)
+	}
+
+	return nil
+}
+
+func printCityLookup(geoNamesTrie *trie.Trie[Entry], query CityQuery) error {
+	matches, err := lookupCities(context.Background(), geoNamesTrie, query)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range matches {
+		name := match.Name
+		if match.Alias != match.Name {
+			name = fmt.Sprintf("%s [%s]", match.Name, match.Alias)
+		}
+
+		fmt.Printf(
+			"%s (%s) %f,%f distance %d",
+			name,
+			match.Location.Country,
+			match.Location.Latitude,
+			match.Location.Longitude,
+			match.Distance,
+		)
+
+		if query.Reference != nil {
+			fmt.Printf(", %.1f km away", match.DistanceKm)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
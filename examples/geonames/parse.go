@@ -5,27 +5,39 @@ import (
 	"fmt"
 	"github.com/marcadamsge/gofuzzy/trie"
 	"io"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
 type GeoLocation struct {
+	// Name is the main name of the location, it may be indexed under other names as well
+	Name      string
 	Latitude  float32
 	Longitude float32
 	Country   string
 }
 
 type Entry struct {
+	// Name is the name indexed in the trie, the main name or an alias of the locations
 	Name string
 	// could be two locations have the same name
 	LocationSet map[*GeoLocation]struct{}
 }
 
-func parseGeoNamesFile(geoNamesReader io.Reader) (*trie.Trie[Entry], uint32, error) {
+// parseGeoNamesFile indexes the cities of the geonames file by name. If withAliases is true, the cities are also
+// indexed by their ASCII name and alternate names.
+// If bulk is true, the names are sorted and the trie is built at once with trie.BuildParallel instead of inserting
+// them one by one.
+// It returns the trie and the number of cities parsed.
+func parseGeoNamesFile(geoNamesReader io.Reader, withAliases bool, bulk bool) (*trie.Trie[Entry], uint32, error) {
 	geoNamesScanner := bufio.NewScanner(geoNamesReader)
 	genNamesTrie := trie.New[Entry]()
+	var entries []trie.KeyValue[Entry]
 	linesParsed := uint32(0)
+	namesInserted := uint32(0)
 	startTime := time.Now()
 
 	println("loading file...")
@@ -56,6 +68,7 @@ func parseGeoNamesFile(geoNamesReader io.Reader) (*trie.Trie[Entry], uint32, err
 		}
 
 		location := &GeoLocation{
+			Name:      name,
 			Latitude:  float32(latitude),
 			Longitude: float32(longitude),
 			Country:   line[8],
@@ -63,25 +76,71 @@ func parseGeoNamesFile(geoNamesReader io.Reader) (*trie.Trie[Entry], uint32, err
 
 		linesParsed++
 
-		entry := &Entry{
-			Name:        name,
-			LocationSet: map[*GeoLocation]struct{}{location: {}},
+		names := []string{name}
+		if withAliases {
+			names = cityAliases(name, line[2], line[3])
 		}
 
-		genNamesTrie.Insert(name, entry, combineEntries)
+		for _, alias := range names {
+			// every name gets its own entry, the entries are merged with the ones of other cities by combineEntries
+			// so they can't be shared between names
+			entry := &Entry{
+				Name:        alias,
+				LocationSet: map[*GeoLocation]struct{}{location: {}},
+			}
+
+			if bulk {
+				entries = append(entries, trie.KeyValue[Entry]{Key: alias, Value: entry})
+			} else {
+				genNamesTrie.Insert(alias, entry, combineEntries)
+			}
+			namesInserted++
+		}
 	}
 
 	if geoNamesScanner.Err() != nil {
 		return nil, 0, geoNamesScanner.Err()
 	}
 
+	if bulk {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Key < entries[j].Key
+		})
+
+		var err error
+		genNamesTrie, err = trie.BuildParallel[Entry](entries, runtime.NumCPU(), combineEntries)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
 	totalTime := time.Now().Sub(startTime)
 	fmt.Printf("dataset loaded in %f seconds\n", totalTime.Seconds())
-	fmt.Printf("%d lines parsed, %d elements inserted in the trie\n", linesParsed, linesParsed)
+	fmt.Printf("%d lines parsed, %d elements inserted in the trie\n", linesParsed, namesInserted)
 
 	return genNamesTrie, linesParsed, nil
 }
 
+// cityAliases returns the distinct non-empty names of a city: its name, ASCII name and the comma separated
+// alternate names.
+func cityAliases(name string, asciiName string, alternateNames string) []string {
+	aliases := []string{name}
+	seen := map[string]struct{}{name: {}}
+
+	candidates := append([]string{asciiName}, strings.Split(alternateNames, ",")...)
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if _, ok := seen[candidate]; ok || len(candidate) == 0 {
+			continue
+		}
+
+		seen[candidate] = struct{}{}
+		aliases = append(aliases, candidate)
+	}
+
+	return aliases
+}
+
 func combineEntries(e1 *Entry, e2 *Entry) *Entry {
 	if e1 != nil && e2 != nil {
 		for k := range e2.LocationSet {
@@ -122,6 +122,7 @@ type testOutput struct {
 	fuzzyLength  int
 	timeTaken    time.Duration
 	resultsFound int
+	stats        fuzzy.Stats
 }
 
 func perfTestWorker(
@@ -155,15 +156,19 @@ func perfTestWorker(
 			)
 		}
 		collector := fuzzy.NewCountCollector[Entry](maxResults)
+		var stats fuzzy.Stats
 
 		start := time.Now()
-		fuzzy.Search[Entry](context.Background(), genNamesTrie, fuzzyName, maxDistance, collector)
+		fuzzy.SearchWithOptions[Entry](context.Background(), genNamesTrie, fuzzyName, maxDistance, collector, fuzzy.Options[Entry]{
+			Stats: &stats,
+		})
 		end := time.Now()
 
 		outputChannel <- testOutput{
 			fuzzyLength:  len(fuzzyName),
 			timeTaken:    end.Sub(start),
 			resultsFound: collector.ResultCount,
+			stats:        stats,
 		}
 	}
 }
@@ -185,6 +190,18 @@ func perfTestCollectResults(inputChannel <-chan testOutput, waitGroup *sync.Wait
 	timeMax := result.timeTaken
 	timeTotal := uint64(result.timeTaken)
 
+	visitedMin := result.stats.NodesVisited
+	visitedMax := result.stats.NodesVisited
+	visitedTotal := uint64(result.stats.NodesVisited)
+
+	pushedMin := result.stats.ItemsPushed
+	pushedMax := result.stats.ItemsPushed
+	pushedTotal := uint64(result.stats.ItemsPushed)
+
+	queueSizeMin := result.stats.PeakQueueSize
+	queueSizeMax := result.stats.PeakQueueSize
+	queueSizeTotal := uint64(result.stats.PeakQueueSize)
+
 	count := uint32(1)
 
 	for ; ok; result, ok = <-inputChannel {
@@ -196,15 +213,33 @@ func perfTestCollectResults(inputChannel <-chan testOutput, waitGroup *sync.Wait
 		timeMax = maxInt(timeMax, result.timeTaken)
 		timeTotal = timeTotal + uint64(result.timeTaken.Nanoseconds())
 
+		visitedMin = minInt(visitedMin, result.stats.NodesVisited)
+		visitedMax = maxInt(visitedMax, result.stats.NodesVisited)
+		visitedTotal = visitedTotal + uint64(result.stats.NodesVisited)
+
+		pushedMin = minInt(pushedMin, result.stats.ItemsPushed)
+		pushedMax = maxInt(pushedMax, result.stats.ItemsPushed)
+		pushedTotal = pushedTotal + uint64(result.stats.ItemsPushed)
+
+		queueSizeMin = minInt(queueSizeMin, result.stats.PeakQueueSize)
+		queueSizeMax = maxInt(queueSizeMax, result.stats.PeakQueueSize)
+		queueSizeTotal = queueSizeTotal + uint64(result.stats.PeakQueueSize)
+
 		count++
 	}
 
 	averageLength := float64(fuzzyLengthTotal) / float64(count)
 	averageTime := float64(timeTotal) / float64(count)
+	averageVisited := float64(visitedTotal) / float64(count)
+	averagePushed := float64(pushedTotal) / float64(count)
+	averageQueueSize := float64(queueSizeTotal) / float64(count)
 
 	println("results:")
 	fmt.Printf("length: %d min, %d max, %f average\n", fuzzyLengthMin, fuzzyLengthMax, averageLength)
 	fmt.Printf("time (in nano second): %d min, %d max, %f average\n", timeMin.Nanoseconds(), timeMax.Nanoseconds(), averageTime)
+	fmt.Printf("nodes visited: %d min, %d max, %f average\n", visitedMin, visitedMax, averageVisited)
+	fmt.Printf("items pushed: %d min, %d max, %f average\n", pushedMin, pushedMax, averagePushed)
+	fmt.Printf("peak queue size: %d min, %d max, %f average\n", queueSizeMin, queueSizeMax, averageQueueSize)
 }
 
 func maxInt[T int | time.Duration](a T, b T) T {
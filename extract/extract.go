@@ -0,0 +1,176 @@
+package extract
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Config tells how entities are looked for in the text.
+type Config struct {
+	// MaxDistance returns the number of edits allowed for an entity of length runes in the text, it should not
+	// decrease with the length. If nil, DefaultMaxDistance is used.
+	MaxDistance func(length int) int
+	// MaxTokens is the largest number of tokens an entity can span, like 2 for "New York". If <= 0, it's 1.
+	MaxTokens int
+	// FoldCase lower cases the text before looking for the entities, the keys of the trie must be lower case too.
+	FoldCase bool
+	// MaxNodesVisited and MaxQueueSize cap the work of the search run at every token, see fuzzy.Options. Once a
+	// search runs out of budget, the entities it did not find yet are missed. They're ignored if <= 0.
+	MaxNodesVisited int
+	MaxQueueSize    int
+}
+
+// DefaultMaxDistance allows no edit for entities up to 2 runes, 1 edit up to 5 runes and 2 edits otherwise.
+func DefaultMaxDistance(length int) int {
+	if length <= 2 {
+		return 0
+	}
+
+	if length <= 5 {
+		return 1
+	}
+
+	return 2
+}
+
+// Span is an entity found in the text.
+type Span[T any] struct {
+	// Start and End are the byte offsets of the entity in the text, Text is text[Start:End]
+	Start int
+	End   int
+	Text  string
+	Value *T
+	// Distance is the number of edits between Text and the key of the entity
+	Distance int
+}
+
+// Extract finds the keys of the trie in the text, allowing some edits. The entities start and end on token
+// boundaries, a token being a sequence of letters and digits, so the separators between the tokens of an entity are
+// matched as well.
+// The spans don't overlap: like the leftmost-longest match of Aho-Corasick, the text is read from left to right and
+// the longest entity starting at a token is kept, then the search resumes after it. If several entities have the same
+// length, the closest one is kept, and then the first one in the order of fuzzy.MatchPrefixes, which is always the
+// same.
+func Extract[T any](ctx context.Context, node *trie.Trie[T], text string, config Config) ([]Span[T], error) {
+	maxDistance := config.MaxDistance
+	if maxDistance == nil {
+		maxDistance = DefaultMaxDistance
+	}
+
+	maxTokens := config.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 1
+	}
+
+	options := fuzzy.Options[T]{
+		MaxNodesVisited: config.MaxNodesVisited,
+		MaxQueueSize:    config.MaxQueueSize,
+	}
+
+	runes, offsets := decodeText(text, config.FoldCase)
+	starts, ends := tokenBoundaries(runes)
+
+	// tokenEnd tells if a token ends at a rune position
+	tokenEnd := make(map[int]struct{}, len(ends))
+	for _, end := range ends {
+		tokenEnd[end] = struct{}{}
+	}
+
+	var spans []Span[T]
+	for i := 0; i < len(starts); {
+		start := starts[i]
+		// an entity can't go further than the end of its last token
+		windowEnd := ends[minInt(i+maxTokens, len(ends))-1]
+		// the trie is explored with the largest distance an entity of the window can have
+		searchDistance := maxDistance(windowEnd - start)
+
+		matches, _, err := fuzzy.MatchPrefixesWithOptions[T](ctx, node, string(runes[start:windowEnd]), searchDistance, options)
+		if err != nil {
+			return spans, err
+		}
+
+		var best *fuzzy.PrefixMatch[T]
+		for j := range matches {
+			match := &matches[j]
+			if _, ok := tokenEnd[start+match.Length]; !ok || match.Distance > maxDistance(match.Length) {
+				continue
+			}
+
+			if best == nil || match.Length > best.Length || (match.Length == best.Length && match.Distance < best.Distance) {
+				best = match
+			}
+		}
+
+		if best == nil {
+			i++
+			continue
+		}
+
+		end := start + best.Length
+		spans = append(spans, Span[T]{
+			Start:    offsets[start],
+			End:      offsets[end],
+			Text:     text[offsets[start]:offsets[end]],
+			Value:    best.Value,
+			Distance: best.Distance,
+		})
+
+		// resume after the entity
+		for i < len(starts) && starts[i] < end {
+			i++
+		}
+	}
+
+	return spans, nil
+}
+
+// decodeText returns the runes of the text, and the byte offset of every rune followed by the length of the text.
+func decodeText(text string, foldCase bool) ([]rune, []int) {
+	runes := make([]rune, 0, len(text))
+	offsets := make([]int, 0, len(text)+1)
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if foldCase {
+			r = unicode.ToLower(r)
+		}
+
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+		i += size
+	}
+	offsets = append(offsets, len(text))
+
+	return runes, offsets
+}
+
+// tokenBoundaries returns the rune positions where the tokens start and end.
+func tokenBoundaries(runes []rune) (starts []int, ends []int) {
+	inToken := false
+	for i, r := range runes {
+		isToken := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isToken && !inToken {
+			starts = append(starts, i)
+		} else if !isToken && inToken {
+			ends = append(ends, i)
+		}
+		inToken = isToken
+	}
+
+	if inToken {
+		ends = append(ends, len(runes))
+	}
+
+	return starts, ends
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,122 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"strings"
+	"testing"
+)
+
+func newTestTrie(keys ...string) *trie.Trie[string] {
+	testTrie := trie.New[string]()
+	for i := range keys {
+		testTrie.Insert(keys[i], &keys[i], trie.KeepFirst[string])
+	}
+
+	return testTrie
+}
+
+func spansString(spans []Span[string]) string {
+	var out []string
+	for _, span := range spans {
+		out = append(out, fmt.Sprintf("%s=%s:%d", span.Text, *span.Value, span.Distance))
+	}
+
+	return strings.Join(out, "|")
+}
+
+func TestExtract(t *testing.T) {
+	cities := newTestTrie("new york", "york", "bern", "saint-étienne", "zürich", "paris")
+
+	for _, test := range []struct {
+		text     string
+		config   Config
+		expected string
+	}{
+		{
+			text:     "I flew from New York to Bern.",
+			config:   Config{MaxTokens: 2, FoldCase: true},
+			expected: "New York=new york:0|Bern=bern:0",
+		},
+		{
+			text:     "from new yrok to zurich via Saint Etienne",
+			config:   Config{MaxTokens: 2, FoldCase: true},
+			expected: "new yrok=new york:1|zurich=zürich:1|Saint Etienne=saint-étienne:2",
+		},
+		{
+			// new york spans two tokens
+			text:     "from new york",
+			config:   Config{MaxTokens: 1},
+			expected: "york=york:0",
+		},
+		{
+			// the entities must end on a token boundary
+			text:     "Bernard and Parisian",
+			config:   Config{FoldCase: true},
+			expected: "",
+		},
+		{
+			text:     "paris bern",
+			config:   Config{MaxDistance: func(int) int { return 0 }},
+			expected: "paris=paris:0|bern=bern:0",
+		},
+	} {
+		spans, err := Extract[string](context.Background(), cities, test.text, test.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if spansString(spans) != test.expected {
+			t.Fatalf("expected %s in '%s' but got %s", test.expected, test.text, spansString(spans))
+		}
+
+		for _, span := range spans {
+			if test.text[span.Start:span.End] != span.Text {
+				t.Fatalf("unexpected offsets for %s", span.Text)
+			}
+		}
+	}
+}
+
+func TestExtractCanBeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Extract[string](ctx, newTestTrie("bern"), "bern", Config{})
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+}
+
+func TestExtractBudget(t *testing.T) {
+	testTrie := newTestTrie("bern", "zurich")
+
+	spans, err := Extract[string](context.Background(), testTrie, "from bern to zurich", Config{})
+	if err != nil || len(spans) != 2 {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+
+	// a budget of one node is not enough to step into the trie
+	spans, err = Extract[string](context.Background(), testTrie, "from bern to zurich", Config{MaxNodesVisited: 1})
+	if err != nil || len(spans) != 0 {
+		t.Fatalf("the searches should of run out of budget, got %v", spans)
+	}
+}
+
+func TestExtractTiesAreDeterministic(t *testing.T) {
+	// all the keys are 1 edit away from bxrn
+	testTrie := newTestTrie("bern", "barn", "born", "burn", "birn")
+
+	first, err := Extract[string](context.Background(), testTrie, "bxrn", Config{})
+	if err != nil || len(first) != 1 {
+		t.Fatalf("unexpected spans %v", first)
+	}
+
+	for i := 0; i < 50; i++ {
+		spans, err := Extract[string](context.Background(), testTrie, "bxrn", Config{})
+		if err != nil || len(spans) != 1 || spans[0].Value != first[0].Value {
+			t.Fatalf("the tie should always be broken the same way, got %v and then %v", *first[0].Value, *spans[0].Value)
+		}
+	}
+}
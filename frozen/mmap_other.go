@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package frozen
+
+import "os"
+
+// Open reads the file and loads the trie from it, see Load. The file can't be memory mapped on this platform, so it's
+// read in memory instead.
+func Open(path string) (*Trie, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(data)
+}
@@ -0,0 +1,44 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package frozen
+
+import (
+	"os"
+	"syscall"
+)
+
+// Open maps the file in memory and loads the trie from it, see Load. The trie must be closed once it's not used
+// anymore.
+func Open(path string) (*Trie, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() == 0 {
+		return nil, ErrInvalidFormat
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	trie, err := Load(data)
+	if err != nil {
+		_ = syscall.Munmap(data)
+		return nil, err
+	}
+
+	trie.close = func() error {
+		return syscall.Munmap(data)
+	}
+
+	return trie, nil
+}
@@ -0,0 +1,227 @@
+package frozen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// The file starts with a header, followed by the nodes, the edges, the values and the offsets of the values:
+//   - header: magic (8 bytes), version, number of nodes, number of edges, number of values (uint32 each), 8 reserved
+//     bytes
+//   - node: index of the first edge, number of edges, index of the value + 1 or 0 if there's none (uint32 each)
+//   - edge: rune, index of the target node (uint32 each), the edges of a node are sorted by rune
+//   - values: the encoded values one after the other
+//   - offsets: the offset of every value from the start of the values, followed by the size of the values (uint64 each)
+//
+// Every integer is little endian, and the root is the first node.
+const (
+	magic      = "GOFUZZY\x00"
+	version    = 1
+	headerSize = 32
+	nodeSize   = 12
+	edgeSize   = 8
+	offsetSize = 8
+)
+
+var ErrInvalidFormat = errors.New("invalid frozen trie")
+
+// Trie is a read-only trie stored in a flat byte slice, usually a memory mapped file. The nodes are read straight
+// from the bytes, nothing is decoded when the trie is loaded, so it starts instantly and several processes mapping
+// the same file share the same memory.
+// The values are stored as bytes, see Write for how they're encoded.
+type Trie struct {
+	nodes   []byte
+	edges   []byte
+	values  []byte
+	offsets []byte
+	// close unmaps the file, it's nil if the trie was not opened with Open
+	close func() error
+}
+
+// Load reads a trie from data, as written by Write. data is used as is, it must not be modified while the trie is used.
+// The nodes, edges and offsets are checked in a single pass without decoding anything, so that a corrupted file returns
+// ErrInvalidFormat instead of making the trie panic later.
+func Load(data []byte) (*Trie, error) {
+	if len(data) < headerSize || string(data[:len(magic)]) != magic {
+		return nil, ErrInvalidFormat
+	}
+
+	if v := binary.LittleEndian.Uint32(data[8:]); v != version {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidFormat, v)
+	}
+
+	nodeCount := uint64(binary.LittleEndian.Uint32(data[12:]))
+	edgeCount := uint64(binary.LittleEndian.Uint32(data[16:]))
+	valueCount := uint64(binary.LittleEndian.Uint32(data[20:]))
+
+	nodesEnd := headerSize + nodeCount*nodeSize
+	edgesEnd := nodesEnd + edgeCount*edgeSize
+	offsetsSize := (valueCount + 1) * offsetSize
+	if nodeCount == 0 || uint64(len(data)) < edgesEnd+offsetsSize {
+		return nil, ErrInvalidFormat
+	}
+
+	offsetsStart := uint64(len(data)) - offsetsSize
+	valuesSize := binary.LittleEndian.Uint64(data[len(data)-offsetSize:])
+	if edgesEnd+valuesSize != offsetsStart {
+		return nil, ErrInvalidFormat
+	}
+
+	trie := &Trie{
+		nodes:   data[headerSize:nodesEnd],
+		edges:   data[nodesEnd:edgesEnd],
+		values:  data[edgesEnd:offsetsStart],
+		offsets: data[offsetsStart:],
+	}
+
+	if err := trie.validate(nodeCount, edgeCount, valueCount, valuesSize); err != nil {
+		return nil, err
+	}
+
+	return trie, nil
+}
+
+// validate checks that every node, edge and offset points inside the trie: the edges of a node and their targets
+// exist, the value indexes are in range and the offsets are increasing up to the size of the values.
+func (trie *Trie) validate(nodeCount uint64, edgeCount uint64, valueCount uint64, valuesSize uint64) error {
+	for i := uint64(0); i < nodeCount; i++ {
+		node := trie.nodes[i*nodeSize:]
+		firstEdge := uint64(binary.LittleEndian.Uint32(node))
+		nodeEdges := uint64(binary.LittleEndian.Uint32(node[4:]))
+		if firstEdge+nodeEdges > edgeCount {
+			return fmt.Errorf("%w: the edges of node %d are out of range", ErrInvalidFormat, i)
+		}
+
+		if valueIndex := uint64(binary.LittleEndian.Uint32(node[8:])); valueIndex > valueCount {
+			return fmt.Errorf("%w: the value of node %d is out of range", ErrInvalidFormat, i)
+		}
+	}
+
+	for i := uint64(0); i < edgeCount; i++ {
+		if target := uint64(binary.LittleEndian.Uint32(trie.edges[i*edgeSize+4:])); target >= nodeCount {
+			return fmt.Errorf("%w: the target of edge %d is out of range", ErrInvalidFormat, i)
+		}
+	}
+
+	previous := uint64(0)
+	for i := uint64(0); i <= valueCount; i++ {
+		offset := binary.LittleEndian.Uint64(trie.offsets[i*offsetSize:])
+		if offset < previous || offset > valuesSize {
+			return fmt.Errorf("%w: the offset of value %d is out of range", ErrInvalidFormat, i)
+		}
+		previous = offset
+	}
+
+	return nil
+}
+
+// Close releases the memory mapping of a trie opened with Open, the trie and its values can't be used anymore.
+func (trie *Trie) Close() error {
+	if trie.close == nil {
+		return nil
+	}
+
+	err := trie.close()
+	trie.close = nil
+	return err
+}
+
+// Len returns the number of values.
+func (trie *Trie) Len() int {
+	return len(trie.offsets)/offsetSize - 1
+}
+
+// Bytes returns the size of the trie data, to compare it with trie.Stats. When the trie is memory mapped, this memory
+// is shared with the other processes mapping the same file.
+func (trie *Trie) Bytes() int {
+	return headerSize + len(trie.nodes) + len(trie.edges) + len(trie.values) + len(trie.offsets)
+}
+
+// Root returns the root of the trie, it can be given to fuzzy.SearchNode.
+func (trie *Trie) Root() Node {
+	return Node{trie: trie, index: 0}
+}
+
+// Get returns the value of the key, or false if the key is not in the trie.
+// The returned slice points to the trie data, it must not be modified.
+func (trie *Trie) Get(key string) ([]byte, bool) {
+	node := trie.Root()
+	for _, r := range key {
+		node = node.Step(r)
+		if node.trie == nil {
+			return nil, false
+		}
+	}
+
+	value := node.GetValue()
+	if value == nil {
+		return nil, false
+	}
+
+	return *value, true
+}
+
+// Node is a node of a frozen trie, it implements fuzzy.Node[Node, []byte].
+type Node struct {
+	trie  *Trie
+	index uint32
+}
+
+func (node Node) field(i int) uint32 {
+	return binary.LittleEndian.Uint32(node.trie.nodes[int(node.index)*nodeSize+4*i:])
+}
+
+func (node Node) edge(i uint32) (rune, uint32) {
+	edge := node.trie.edges[uint64(i)*edgeSize:]
+	return rune(binary.LittleEndian.Uint32(edge)), binary.LittleEndian.Uint32(edge[4:])
+}
+
+// Step out with the rune r and return the next node, or the zero Node if there is none.
+func (node Node) Step(r rune) Node {
+	firstEdge := node.field(0)
+	edgeCount := int(node.field(1))
+
+	i := sort.Search(edgeCount, func(i int) bool {
+		edgeRune, _ := node.edge(firstEdge + uint32(i))
+		return edgeRune >= r
+	})
+	if i == edgeCount {
+		return Node{}
+	}
+
+	edgeRune, target := node.edge(firstEdge + uint32(i))
+	if edgeRune != r {
+		return Node{}
+	}
+
+	return Node{trie: node.trie, index: target}
+}
+
+// Iterate over all the children of this node, by increasing rune.
+func (node Node) Iterate(iterationFunction func(r rune, node Node)) {
+	firstEdge := node.field(0)
+	edgeCount := node.field(1)
+
+	for i := uint32(0); i < edgeCount; i++ {
+		r, target := node.edge(firstEdge + i)
+		iterationFunction(r, Node{trie: node.trie, index: target})
+	}
+}
+
+// GetValue returns the value of this node, or nil if there is none. The value points to the trie data, it must not
+// be modified.
+func (node Node) GetValue() *[]byte {
+	valueIndex := node.field(2)
+	if valueIndex == 0 {
+		return nil
+	}
+
+	offsets := node.trie.offsets[uint64(valueIndex-1)*offsetSize:]
+	start := binary.LittleEndian.Uint64(offsets)
+	end := binary.LittleEndian.Uint64(offsets[offsetSize:])
+	value := node.trie.values[start:end:end]
+
+	return &value
+}
@@ -0,0 +1,192 @@
+package frozen
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var testWords = []string{"", "cat", "tat", "dog", "cart", "card", "bat", "at", "catalog", "dot", "⌘at", "scat", "bart"}
+
+func writeTestTrie(t *testing.T) []byte {
+	testTrie := trie.New[string]()
+	for i := range testWords {
+		testTrie.Insert(testWords[i], &testWords[i], trie.KeepFirst[string])
+	}
+
+	var buffer bytes.Buffer
+	err := Write[string](&buffer, testTrie, func(value *string) ([]byte, error) {
+		return []byte(strings.ToUpper(*value)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestLoad(t *testing.T) {
+	data := writeTestTrie(t)
+	frozenTrie, err := Load(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frozenTrie.Bytes() != len(data) {
+		t.Fatalf("expected %d bytes but got %d", len(data), frozenTrie.Bytes())
+	}
+
+	if frozenTrie.Len() != len(testWords) {
+		t.Fatalf("expected %d values but got %d", len(testWords), frozenTrie.Len())
+	}
+
+	for _, word := range testWords {
+		value, ok := frozenTrie.Get(word)
+		if !ok || string(value) != strings.ToUpper(word) {
+			t.Fatalf("unexpected value %s for %s", value, word)
+		}
+	}
+
+	for _, word := range []string{"ca", "cats", "⌘", "x"} {
+		if _, ok := frozenTrie.Get(word); ok {
+			t.Fatalf("%s should not be in the trie", word)
+		}
+	}
+
+	var runes []rune
+	frozenTrie.Root().Step('c').Step('a').Iterate(func(r rune, _ Node) {
+		runes = append(runes, r)
+	})
+	if string(runes) != "rt" {
+		t.Fatalf("unexpected children %s", string(runes))
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	data := writeTestTrie(t)
+
+	for _, invalid := range [][]byte{nil, data[:headerSize], data[:len(data)-1], append([]byte("NOTFUZZY"), data[8:]...)} {
+		if _, err := Load(invalid); !errors.Is(err, ErrInvalidFormat) {
+			t.Fatalf("expected ErrInvalidFormat but got %v", err)
+		}
+	}
+}
+
+func TestLoadCorrupted(t *testing.T) {
+	data := writeTestTrie(t)
+	nodeCount := int(binary.LittleEndian.Uint32(data[12:]))
+	edgeCount := int(binary.LittleEndian.Uint32(data[16:]))
+	valueCount := int(binary.LittleEndian.Uint32(data[20:]))
+	edgesStart := headerSize + nodeCount*nodeSize
+	offsetsStart := len(data) - (valueCount+1)*offsetSize
+
+	corruptions := map[string]func(corrupted []byte){
+		"first edge": func(corrupted []byte) {
+			binary.LittleEndian.PutUint32(corrupted[headerSize:], uint32(edgeCount))
+		},
+		"edge count": func(corrupted []byte) {
+			binary.LittleEndian.PutUint32(corrupted[headerSize+4:], uint32(edgeCount+1))
+		},
+		"value index": func(corrupted []byte) {
+			binary.LittleEndian.PutUint32(corrupted[headerSize+8:], uint32(valueCount+1))
+		},
+		"edge target": func(corrupted []byte) {
+			binary.LittleEndian.PutUint32(corrupted[edgesStart+4:], uint32(nodeCount))
+		},
+		"decreasing offsets": func(corrupted []byte) {
+			binary.LittleEndian.PutUint64(corrupted[offsetsStart+offsetSize:], 0)
+			binary.LittleEndian.PutUint64(corrupted[offsetsStart:], 1)
+		},
+		"offset past the values": func(corrupted []byte) {
+			binary.LittleEndian.PutUint64(corrupted[offsetsStart+offsetSize:], uint64(offsetsStart))
+		},
+	}
+
+	for name, corrupt := range corruptions {
+		corrupted := append([]byte(nil), data...)
+		corrupt(corrupted)
+		if _, err := Load(corrupted); !errors.Is(err, ErrInvalidFormat) {
+			t.Fatalf("%s: expected ErrInvalidFormat but got %v", name, err)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.idx")
+	if err := os.WriteFile(path, writeTestTrie(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	frozenTrie, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, ok := frozenTrie.Get("catalog"); !ok || string(value) != "CATALOG" {
+		t.Fatalf("unexpected value %s", value)
+	}
+
+	if err := frozenTrie.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	testTrie := trie.New[string]()
+	for i := range testWords {
+		testTrie.Insert(testWords[i], &testWords[i], trie.KeepFirst[string])
+	}
+
+	frozenTrie, err := Load(writeTestTrie(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{"cat", "at", "", "dgo", "catalgo", "xyz", "bt"} {
+		for distance := 0; distance <= 3; distance++ {
+			for _, options := range []fuzzy.Options[[]byte]{{}, {Workers: 3}, {Prefix: true}} {
+				expected := fuzzy.NewListCollector[string](-1)
+				_, err := fuzzy.SearchWithOptions[string](context.Background(), testTrie, query, distance, expected, fuzzy.Options[string]{
+					Workers: options.Workers,
+					Prefix:  options.Prefix,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual := fuzzy.NewListCollector[[]byte](-1)
+				_, err = fuzzy.SearchNode[Node, []byte](context.Background(), frozenTrie.Root(), query, distance, actual, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var expectedResults []string
+				for _, result := range expected.Results {
+					expectedResults = append(expectedResults, fmt.Sprintf("%d:%s", result.Distance, strings.ToUpper(*result.Value)))
+				}
+
+				var actualResults []string
+				for _, result := range actual.Results {
+					actualResults = append(actualResults, fmt.Sprintf("%d:%s", result.Distance, *result.Value))
+				}
+
+				// the results at the same distance may come in any order
+				sort.Strings(expectedResults)
+				sort.Strings(actualResults)
+				if strings.Join(expectedResults, "|") != strings.Join(actualResults, "|") {
+					t.Fatalf("search %s with distance %d and options %+v: expected %v but got %v",
+						query, distance, options, expectedResults, actualResults)
+				}
+			}
+		}
+	}
+}
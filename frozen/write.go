@@ -0,0 +1,131 @@
+package frozen
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"io"
+	"math"
+)
+
+// Write serializes the trie in the format read by Load and Open, encode turns every value into bytes.
+// The nodes are written depth first with the children sorted by rune.
+func Write[T any](writer io.Writer, t *trie.Trie[T], encode func(value *T) ([]byte, error)) error {
+	type frozenNode struct {
+		node      *trie.Trie[T]
+		runes     []rune
+		firstEdge uint32
+		// children holds the index of the children, in the order of runes
+		children []uint32
+	}
+
+	// number the nodes depth first, so that a whole subtree is stored in a contiguous part of the file
+	var nodes []*frozenNode
+	edgeCount := uint64(0)
+	valueCount := uint64(0)
+
+	var number func(node *trie.Trie[T]) uint32
+	number = func(node *trie.Trie[T]) uint32 {
+		index := uint32(len(nodes))
+		n := &frozenNode{node: node}
+		nodes = append(nodes, n)
+
+		if node.Value != nil {
+			valueCount++
+		}
+
+		node.IterateSorted(func(r rune, _ *trie.Trie[T]) {
+			n.runes = append(n.runes, r)
+		})
+
+		n.firstEdge = uint32(edgeCount)
+		edgeCount += uint64(len(n.runes))
+
+		n.children = make([]uint32, 0, len(n.runes))
+		for _, r := range n.runes {
+			n.children = append(n.children, number(node.Step(r)))
+		}
+
+		return index
+	}
+	number(t)
+
+	if uint64(len(nodes)) > math.MaxUint32 || edgeCount > math.MaxUint32 || valueCount >= math.MaxUint32 {
+		return errors.New("the trie is too large")
+	}
+
+	out := bufio.NewWriter(writer)
+	buffer := make([]byte, 0, headerSize)
+
+	buffer = append(buffer, magic...)
+	buffer = appendUint32(buffer, version)
+	buffer = appendUint32(buffer, uint32(len(nodes)))
+	buffer = appendUint32(buffer, uint32(edgeCount))
+	buffer = appendUint32(buffer, uint32(valueCount))
+	buffer = append(buffer, make([]byte, headerSize-len(buffer))...)
+	if _, err := out.Write(buffer); err != nil {
+		return err
+	}
+
+	valueIndex := uint32(0)
+	for _, n := range nodes {
+		buffer = appendUint32(buffer[:0], n.firstEdge)
+		buffer = appendUint32(buffer, uint32(len(n.runes)))
+		if n.node.Value != nil {
+			valueIndex++
+			buffer = appendUint32(buffer, valueIndex)
+		} else {
+			buffer = appendUint32(buffer, 0)
+		}
+
+		if _, err := out.Write(buffer); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range nodes {
+		for i, r := range n.runes {
+			buffer = appendUint32(buffer[:0], uint32(r))
+			buffer = appendUint32(buffer, n.children[i])
+			if _, err := out.Write(buffer); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the values are written in the order of their index, the offsets are written once they're all known
+	offsets := make([]uint64, 0, valueCount+1)
+	valuesSize := uint64(0)
+	for _, n := range nodes {
+		if n.node.Value == nil {
+			continue
+		}
+
+		encoded, err := encode(n.node.Value)
+		if err != nil {
+			return err
+		}
+
+		offsets = append(offsets, valuesSize)
+		valuesSize += uint64(len(encoded))
+		if _, err := out.Write(encoded); err != nil {
+			return err
+		}
+	}
+	offsets = append(offsets, valuesSize)
+
+	for _, offset := range offsets {
+		buffer = buffer[:offsetSize]
+		binary.LittleEndian.PutUint64(buffer, offset)
+		if _, err := out.Write(buffer); err != nil {
+			return err
+		}
+	}
+
+	return out.Flush()
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
@@ -0,0 +1,281 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/internal/queue"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+	"sync"
+)
+
+// minSharedPrefix is the minimum number of runes queries must have in common for their traversal to be shared.
+const minSharedPrefix = 3
+
+// Query is a single search of a batch, the results are given to Collector the same way Search would.
+type Query[T any] struct {
+	Str       string
+	Distance  int
+	Collector ResultCollector[T]
+}
+
+// SearchBatch runs all the queries on the trie using a pool of workers goroutines, and returns once they're all done.
+// The queries are sorted so that queries sharing a prefix are run together: the part of the trie matching their
+// common prefix is only explored once for all of them.
+// A collector is only used by one goroutine at a time, but different collectors are used concurrently.
+func SearchBatch[T any](ctx context.Context, node *trie.Trie[T], queries []Query[T], workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	sorted := make([]batchQuery[T], 0, len(queries))
+	for i := range queries {
+		sorted = append(sorted, batchQuery[T]{
+			query: &queries[i],
+			runes: []rune(queries[i].Str),
+		})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].query.Distance != sorted[j].query.Distance {
+			return sorted[i].query.Distance < sorted[j].query.Distance
+		}
+
+		return sorted[i].query.Str < sorted[j].query.Str
+	})
+
+	groupChannel := make(chan batchGroup[T], 4*workers)
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go batchWorker[T](ctx, node, groupChannel, &waitGroup)
+	}
+
+	doneCh := ctx.Done()
+
+Loop:
+	for _, group := range groupQueries[T](sorted) {
+		select {
+		case groupChannel <- group:
+		case <-doneCh:
+			break Loop
+		}
+	}
+
+	close(groupChannel)
+	waitGroup.Wait()
+}
+
+// SearchStream runs the queries read from the channel on a pool of workers goroutines,
+// until the channel is closed or the context is canceled.
+// Contrary to SearchBatch, the queries are not sorted and don't share any work.
+func SearchStream[T any](ctx context.Context, node *trie.Trie[T], queries <-chan Query[T], workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer waitGroup.Done()
+
+			doneCh := ctx.Done()
+			for {
+				select {
+				case <-doneCh:
+					return
+				case query, ok := <-queries:
+					if !ok {
+						return
+					}
+
+					Search[T](ctx, node, query.Str, query.Distance, query.Collector)
+				}
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+}
+
+type batchQuery[T any] struct {
+	query *Query[T]
+	runes []rune
+}
+
+// batchGroup is a list of queries with the same distance sharing their first prefixLength runes.
+type batchGroup[T any] struct {
+	queries      []batchQuery[T]
+	prefixLength int
+}
+
+// groupQueries splits the sorted queries into groups sharing a prefix.
+func groupQueries[T any](sorted []batchQuery[T]) []batchGroup[T] {
+	var groups []batchGroup[T]
+
+	for start := 0; start < len(sorted); {
+		prefixLength := len(sorted[start].runes)
+		end := start + 1
+
+		for ; end < len(sorted) && sorted[end].query.Distance == sorted[start].query.Distance; end++ {
+			shared := commonPrefixLength(sorted[start].runes[:prefixLength], sorted[end].runes)
+			if shared < minSharedPrefix {
+				break
+			}
+
+			prefixLength = shared
+		}
+
+		if end-start == 1 {
+			// nothing to share
+			prefixLength = 0
+		}
+
+		groups = append(groups, batchGroup[T]{
+			queries:      sorted[start:end],
+			prefixLength: prefixLength,
+		})
+		start = end
+	}
+
+	return groups
+}
+
+func commonPrefixLength(r1 []rune, r2 []rune) int {
+	i := 0
+	for ; i < len(r1) && i < len(r2) && r1[i] == r2[i]; i++ {
+	}
+
+	return i
+}
+
+func batchWorker[T any](
+	ctx context.Context,
+	node *trie.Trie[T],
+	groupChannel <-chan batchGroup[T],
+	waitGroup *sync.WaitGroup,
+) {
+	defer waitGroup.Done()
+
+	for group := range groupChannel {
+		if ctx.Err() != nil {
+			// keep reading so that the producer is never blocked
+			continue
+		}
+
+		if group.prefixLength == 0 {
+			for _, q := range group.queries {
+				Search[T](ctx, node, q.query.Str, q.query.Distance, q.query.Collector)
+			}
+
+			continue
+		}
+
+		distance := group.queries[0].query.Distance
+		prefix := group.queries[0].runes[:group.prefixLength]
+		frontier, boundary := exploreSharedPrefix[T](ctx, node, prefix, distance)
+
+		for _, q := range group.queries {
+			seed := frontier
+
+			// the swap of the last rune of the prefix with the next one depends on the query
+			if len(q.runes) > len(prefix) {
+				seed = append([]*queue.Item[*trie.Trie[T]](nil), frontier...)
+				for _, item := range boundary {
+					step1 := item.Step.Step(q.runes[len(prefix)])
+					if step1 == nil {
+						continue
+					}
+
+					step2 := step1.Step(q.runes[len(prefix)-1])
+					if step2 != nil {
+						seed = append(seed, &queue.Item[*trie.Trie[T]]{
+							Position:   len(prefix) + 1,
+							Step:       step2,
+							ErrorsLeft: item.ErrorsLeft - 1,
+						})
+					}
+				}
+			}
+
+			s := &searcher[*trie.Trie[T], T]{
+				root:     node,
+				runes:    q.runes,
+				distance: distance,
+				ownsRoot: true,
+				seed:     seed,
+			}
+			s.run(ctx, q.query.Collector.Collect, q.query.Collector.Done, nil)
+		}
+	}
+}
+
+// exploreSharedPrefix explores all the states of the trie that can be reached while reading the prefix.
+// The frontier holds the items that went past the prefix, those are the same for every query starting with it.
+// The boundary holds the items on the last rune of the prefix that may still swap it with the rune following
+// the prefix, which is specific to each query.
+func exploreSharedPrefix[T any](
+	ctx context.Context,
+	node *trie.Trie[T],
+	prefix []rune,
+	distance int,
+) (frontier []*queue.Item[*trie.Trie[T]], boundary []*queue.Item[*trie.Trie[T]]) {
+	type visitKey struct {
+		step     *trie.Trie[T]
+		position int
+	}
+
+	priorityQueue := queue.New[*trie.Trie[T]]()
+	for _, item := range rootSeed(node, distance) {
+		priorityQueue.Add(item)
+	}
+
+	// the items are popped by decreasing number of errors left, so the first visit of a state is always the best one
+	visited := make(map[visitKey]struct{})
+	frontierIndex := make(map[visitKey]int)
+	maxPosition := len(prefix)
+
+	add := func(item *queue.Item[*trie.Trie[T]]) {
+		if item.Position < maxPosition {
+			priorityQueue.Add(item)
+			return
+		}
+
+		// only keep the item with the most errors left for every state of the frontier
+		key := visitKey{step: item.Step, position: item.Position}
+		if i, ok := frontierIndex[key]; !ok {
+			frontierIndex[key] = len(frontier)
+			frontier = append(frontier, item)
+		} else if frontier[i].ErrorsLeft < item.ErrorsLeft {
+			frontier[i] = item
+		}
+	}
+
+	// the searcher only reads the prefix, the items going past it are not popped
+	s := &searcher[*trie.Trie[T], T]{
+		root:     node,
+		runes:    prefix,
+		distance: distance,
+		ownsRoot: true,
+	}
+
+	for crtItem := priorityQueue.Pop(); crtItem != nil && ctx.Err() == nil; crtItem = priorityQueue.Pop() {
+		key := visitKey{step: crtItem.Step, position: crtItem.Position}
+		if _, ok := visited[key]; ok {
+			continue
+		}
+		visited[key] = struct{}{}
+
+		if crtItem.ErrorsLeft > 0 && crtItem.Position == maxPosition-1 {
+			// the last rune of the prefix can't be swapped without the next one
+			boundary = append(boundary, crtItem)
+		}
+
+		s.expand(crtItem, add)
+	}
+
+	return frontier, boundary
+}
@@ -0,0 +1,119 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"testing"
+)
+
+func newBatchTestTrie() *trie.Trie[string] {
+	words := []string{
+		"", "a", "cat", "cart", "card", "carton", "cartoon", "catalog", "catalogue", "category",
+		"dog", "dot", "doting", "bat", "at", "tac", "cta", "⌘at",
+	}
+	testTrie := trie.New[string]()
+
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepLast[string])
+	}
+
+	return testTrie
+}
+
+func TestSearchBatch(t *testing.T) {
+	testTrie := newBatchTestTrie()
+	strs := []string{
+		"cat", "cat", "catl", "catlaog", "cartoon", "carotn", "cartno", "crat", "ca", "c", "",
+		"dgo", "doitng", "dot", "dto", "x", "catr", "catro", "catalgoue", "categroy", "⌘at", "⌘a",
+	}
+
+	for distance := 0; distance <= 3; distance++ {
+		queries := make([]Query[string], 0, len(strs))
+		for _, str := range strs {
+			queries = append(queries, Query[string]{
+				Str:       str,
+				Distance:  distance,
+				Collector: NewListCollector[string](-1),
+			})
+		}
+
+		SearchBatch[string](context.Background(), testTrie, queries, 3)
+
+		for _, query := range queries {
+			expected := NewListCollector[string](-1)
+			Search[string](context.Background(), testTrie, query.Str, query.Distance, expected)
+
+			checkSameResults(t, query.Str, query.Distance, expected.Results, query.Collector.(*ListCollector[string]).Results)
+		}
+	}
+}
+
+func TestSearchBatchMixedDistances(t *testing.T) {
+	testTrie := newBatchTestTrie()
+	queries := []Query[string]{
+		{Str: "cartono", Distance: 1, Collector: NewListCollector[string](1)},
+		{Str: "cartono", Distance: 2, Collector: NewListCollector[string](2)},
+		{Str: "cartoo", Distance: 0, Collector: NewListCollector[string](1)},
+	}
+
+	SearchBatch[string](context.Background(), testTrie, queries, 1)
+
+	for _, query := range queries {
+		expected := NewListCollector[string](query.Collector.(*ListCollector[string]).MaxResult)
+		Search[string](context.Background(), testTrie, query.Str, query.Distance, expected)
+
+		actual := query.Collector.(*ListCollector[string]).Results
+		if len(actual) != len(expected.Results) {
+			t.Fatalf("query '%s' with distance %d: expected %d results but got %d", query.Str, query.Distance, len(expected.Results), len(actual))
+		}
+
+		for i := range actual {
+			if actual[i].Distance != expected.Results[i].Distance {
+				t.Fatalf("query '%s' with distance %d: unexpected distance at position %d", query.Str, query.Distance, i)
+			}
+		}
+	}
+}
+
+func TestSearchStream(t *testing.T) {
+	testTrie := newBatchTestTrie()
+	queries := make(chan Query[string])
+	collectors := []*CountCollector[string]{
+		NewCountCollector[string](10),
+		NewCountCollector[string](10),
+	}
+
+	go func() {
+		queries <- Query[string]{Str: "cat", Distance: 0, Collector: collectors[0]}
+		queries <- Query[string]{Str: "dgo", Distance: 1, Collector: collectors[1]}
+		close(queries)
+	}()
+
+	SearchStream[string](context.Background(), testTrie, queries, 2)
+
+	if collectors[0].ResultCount != 1 || collectors[1].ResultCount != 1 {
+		t.Fatalf("unexpected result counts %d and %d", collectors[0].ResultCount, collectors[1].ResultCount)
+	}
+}
+
+func TestGroupQueries(t *testing.T) {
+	var sorted []batchQuery[string]
+	for _, str := range []string{"card", "cart", "carton", "cat", "dog"} {
+		query := &Query[string]{Str: str, Distance: 1}
+		sorted = append(sorted, batchQuery[string]{query: query, runes: []rune(str)})
+	}
+
+	groups := groupQueries[string](sorted)
+
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups but got %d", len(groups))
+	}
+
+	if len(groups[0].queries) != 3 || groups[0].prefixLength != 3 {
+		t.Fatalf("unexpected first group: %d queries with a prefix of %d", len(groups[0].queries), groups[0].prefixLength)
+	}
+
+	if len(groups[1].queries) != 1 || groups[1].prefixLength != 0 || len(groups[2].queries) != 1 {
+		t.Fatal("queries without a common prefix should not be grouped")
+	}
+}
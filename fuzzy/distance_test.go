@@ -0,0 +1,71 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/distance"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"math/rand"
+	"testing"
+)
+
+func randomWord(random *rand.Rand, alphabet []rune, maxLength int) string {
+	runes := make([]rune, random.Intn(maxLength+1))
+	for i := range runes {
+		runes[i] = alphabet[random.Intn(len(alphabet))]
+	}
+
+	return string(runes)
+}
+
+// TestSearchDistancesAgreeWithOSA checks that the search finds exactly the keys within the optimal string alignment
+// distance of the query, with their OSA distance.
+func TestSearchDistancesAgreeWithOSA(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	alphabet := []rune("abcé")
+
+	for n := 0; n < 50; n++ {
+		testTrie := trie.New[string]()
+		var words []string
+		for i := 0; i < 100; i++ {
+			word := randomWord(random, alphabet, 7)
+			words = append(words, word)
+			testTrie.Insert(word, &word, trie.KeepFirst[string])
+		}
+
+		for q := 0; q < 20; q++ {
+			query := randomWord(random, alphabet, 7)
+			maxDistance := random.Intn(4)
+
+			expected := make(map[string]int)
+			for _, word := range words {
+				if d := distance.OSA(query, word); d <= maxDistance {
+					expected[word] = d
+				}
+			}
+
+			for _, workers := range []int{1, 3} {
+				collector := NewListCollector[string](-1)
+				_, err := SearchWithOptions[string](context.Background(), testTrie, query, maxDistance, collector, Options[string]{
+					Workers: workers,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(collector.Results) != len(expected) {
+					t.Fatalf("'%s' within %d: expected %d results, got %d", query, maxDistance, len(expected), len(collector.Results))
+				}
+
+				for i, result := range collector.Results {
+					if d, ok := expected[*result.Value]; !ok || d != result.Distance {
+						t.Fatalf("'%s' matched '%s' with distance %d, the OSA distance is %d", query, *result.Value, result.Distance, distance.OSA(query, *result.Value))
+					}
+
+					if i > 0 && result.Distance < collector.Results[i-1].Distance {
+						t.Fatalf("the results of '%s' should be sorted by distance", query)
+					}
+				}
+			}
+		}
+	}
+}
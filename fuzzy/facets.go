@@ -0,0 +1,14 @@
+package fuzzy
+
+import "github.com/marcadamsge/gofuzzy/trie"
+
+// PruneByFacets returns a function to use as Options.Prune when searching faceted.Trie, restricting the search to the
+// branches of the trie holding at least one value with one of the facets. The facets must have been indexed with
+// FacetedTrie.InsertWithFacets, the branches inserted without facets are always skipped.
+// Because a trie knows the facets of its whole subtree, the values themselves still have to be checked with
+// Options.Filter. A nil facets is the empty set, so every branch is skipped.
+func PruneByFacets[T any](faceted *trie.FacetedTrie[T], facets *trie.Facets) func(node *trie.Trie[T]) bool {
+	return func(node *trie.Trie[T]) bool {
+		return !faceted.Facets(node).Intersects(facets)
+	}
+}
@@ -0,0 +1,155 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"reflect"
+	"testing"
+)
+
+type testCity struct {
+	name    string
+	country string
+}
+
+func TestSearchFilter(t *testing.T) {
+	cities := []testCity{
+		{name: "bern", country: "CH"},
+		{name: "berm", country: "US"},
+		{name: "bernay", country: "FR"},
+		{name: "term", country: "CH"},
+	}
+
+	testTrie := trie.New[testCity]()
+	for i := range cities {
+		testTrie.Insert(cities[i].name, &cities[i], trie.KeepLast[testCity])
+	}
+
+	inSwitzerland := func(city *testCity) bool {
+		return city.country == "CH"
+	}
+
+	for _, workers := range []int{1, 2} {
+		collector := NewListCollector[testCity](2)
+		SearchWithOptions[testCity](context.Background(), testTrie, "berm", 1, collector, Options[testCity]{
+			Workers: workers,
+			Filter:  inSwitzerland,
+		})
+
+		// berm is filtered out and does not count toward the 2 results
+		expected := []Result[testCity]{
+			{Value: &cities[0], Distance: 1},
+			{Value: &cities[3], Distance: 1},
+		}
+
+		checkSameCities(t, expected, collector.Results)
+	}
+}
+
+func TestSearchPrune(t *testing.T) {
+	cities := []testCity{
+		{name: "bern", country: "CH"},
+		{name: "berm", country: "US"},
+		{name: "term", country: "CH"},
+	}
+
+	testTrie := trie.New[testCity]()
+	for i := range cities {
+		testTrie.Insert(cities[i].name, &cities[i], trie.KeepLast[testCity])
+	}
+
+	tBranch := testTrie.Step('t')
+	collector := NewListCollector[testCity](-1)
+	SearchWithOptions[testCity](context.Background(), testTrie, "berm", 1, collector, Options[testCity]{
+		Prune: func(node *trie.Trie[testCity]) bool {
+			return node == tBranch
+		},
+	})
+
+	expected := []Result[testCity]{
+		{Value: &cities[1], Distance: 0},
+		{Value: &cities[0], Distance: 1},
+	}
+
+	if !reflect.DeepEqual(expected, collector.Results) {
+		t.Fatal("the t branch should of been pruned")
+	}
+}
+
+func checkSameCities(t *testing.T, expected []Result[testCity], actual []Result[testCity]) {
+	if len(expected) != len(actual) {
+		t.Fatalf("expected %d results but got %d", len(expected), len(actual))
+	}
+
+	for _, e := range expected {
+		found := false
+		for _, a := range actual {
+			found = found || (e.Value == a.Value && e.Distance == a.Distance)
+		}
+
+		if !found {
+			t.Fatalf("result %s is missing", e.Value.name)
+		}
+	}
+}
+
+func TestSearchPruneByFacets(t *testing.T) {
+	countries := map[string]uint32{"CH": 0, "US": 1, "FR": 2}
+	cities := []testCity{
+		{name: "bern", country: "CH"},
+		{name: "berm", country: "US"},
+		{name: "bernay", country: "FR"},
+		{name: "term", country: "CH"},
+		{name: "tern", country: "US"},
+	}
+
+	testTrie := trie.NewFaceted[testCity]()
+	for i := range cities {
+		testTrie.InsertWithFacets(cities[i].name, &cities[i], trie.NewFacets(countries[cities[i].country]), trie.KeepLast[testCity])
+	}
+
+	var stats Stats
+	collector := NewListCollector[testCity](-1)
+	SearchWithOptions[testCity](context.Background(), testTrie.Trie, "bern", 1, collector, Options[testCity]{
+		Filter: func(city *testCity) bool {
+			return city.country == "US"
+		},
+		Prune: PruneByFacets[testCity](testTrie, trie.NewFacets(countries["US"])),
+		Stats: &stats,
+	})
+
+	expected := []Result[testCity]{
+		{Value: &cities[1], Distance: 1},
+		{Value: &cities[4], Distance: 1},
+	}
+
+	checkSameCities(t, expected, collector.Results)
+
+	var unprunedStats Stats
+	SearchWithOptions[testCity](context.Background(), testTrie.Trie, "bern", 1, NewListCollector[testCity](-1), Options[testCity]{
+		Filter: func(city *testCity) bool {
+			return city.country == "US"
+		},
+		Stats: &unprunedStats,
+	})
+
+	if stats.NodesVisited >= unprunedStats.NodesVisited {
+		t.Fatal("pruning by facets should of reduced the number of nodes visited")
+	}
+}
+
+func TestSearchPruneByNilFacets(t *testing.T) {
+	testTrie := trie.NewFaceted[testCity]()
+	bern := testCity{name: "bern", country: "CH"}
+	testTrie.InsertWithFacets(bern.name, &bern, trie.NewFacets(0), trie.KeepLast[testCity])
+
+	collector := NewListCollector[testCity](-1)
+	SearchWithOptions[testCity](context.Background(), testTrie.Trie, "bern", 1, collector, Options[testCity]{
+		Prune: PruneByFacets[testCity](testTrie, nil),
+	})
+
+	// no facet is looked for, so every branch is pruned
+	if len(collector.Results) != 0 {
+		t.Fatalf("unexpected results %+v", collector.Results)
+	}
+}
@@ -0,0 +1,31 @@
+package fuzzy
+
+import "github.com/marcadamsge/gofuzzy/trie"
+
+// Node is implemented by the structures SearchNode can explore, like *trie.Trie[T]. N is the type of the nodes
+// themselves, two nodes are the same node if they're equal.
+type Node[N any, T any] interface {
+	comparable
+	// Step returns the child reached with the rune r, or the zero value of N if there is none.
+	Step(r rune) N
+	// Iterate calls iterationFunction with every child of the node.
+	Iterate(iterationFunction func(r rune, node N))
+	// GetValue returns the value of the node, or nil if no key ends there.
+	GetValue() *T
+}
+
+// pruneNodes adapts Options.Prune, which only knows about tries, to the nodes being explored.
+func pruneNodes[N Node[N, T], T any](prune func(node *trie.Trie[T]) bool) func(node N) bool {
+	if prune == nil {
+		return nil
+	}
+
+	var none N
+	if _, isTrie := any(none).(*trie.Trie[T]); !isTrie {
+		return nil
+	}
+
+	return func(node N) bool {
+		return prune(any(node).(*trie.Trie[T]))
+	}
+}
@@ -0,0 +1,79 @@
+package fuzzy
+
+import (
+	"github.com/marcadamsge/gofuzzy/trie"
+	"time"
+)
+
+// Options tweaks how SearchWithOptions explores the trie.
+// The zero value gives the same behaviour as Search.
+// When Workers > 1, the Filter and Prune hooks are called from several goroutines so they have to be thread safe.
+type Options[T any] struct {
+	// Workers is the number of goroutines sharing the exploration of the children of the root.
+	// If Workers <= 1, the search runs on the calling goroutine.
+	Workers int
+	// Stats is filled with the statistics of the search once it returns, it's ignored if nil.
+	Stats *Stats
+	// Tracer is notified with the statistics of the search once it returns, it's ignored if nil.
+	Tracer Tracer
+	// MaxNodesVisited stops the search once that many items were expanded, it's ignored if <= 0.
+	// For a parallel search, the budget is split evenly between the workers.
+	MaxNodesVisited int
+	// MaxQueueSize stops the search as soon as the priority queue grows beyond that many items, it's ignored if <= 0.
+	// For a parallel search, the budget is split evenly between the workers.
+	MaxQueueSize int
+	// Filter is called on every match before it's given to the collector, if it returns false the match is skipped
+	// and doesn't count towards collector.Done(). It's ignored if nil.
+	Filter func(t *T) bool
+	// Prune is called on the nodes of the trie before they are explored, if it returns true the node and its whole
+	// subtree are skipped. It's a way to use some data aggregated on the nodes to avoid exploring branches where
+	// Filter would reject every value. It's ignored if nil, or if SearchNode explores something else than a trie.
+	Prune func(node *trie.Trie[T]) bool
+	// Prefix matches all the strings starting with a prefix within the distance of the searched string, this can be
+	// used for autocompletion. The values of a subtree are collected in no particular order.
+	Prefix bool
+}
+
+// Stats counts the work done by a single search.
+// For a parallel search, the counters are summed over all the workers.
+type Stats struct {
+	// ItemsPushed is the number of items added to the priority queue
+	ItemsPushed int
+	// NodesVisited is the number of items popped from the priority queue and expanded
+	NodesVisited int
+	// PeakQueueSize is the maximum number of items waiting in the priority queue
+	PeakQueueSize int
+	// Results is the number of matches given to the collector
+	Results int
+	// Duration is the time the search took
+	Duration time.Duration
+}
+
+// Tracer is notified every time a search finishes, it's a way to aggregate the statistics of many searches.
+// A Tracer shared between concurrent searches has to be thread safe.
+type Tracer interface {
+	SearchDone(str string, distance int, stats Stats)
+}
+
+func (options *Options[T]) reportStats(str string, distance int, stats Stats, startTime time.Time) {
+	stats.Duration = time.Now().Sub(startTime)
+
+	if options.Stats != nil {
+		*options.Stats = stats
+	}
+
+	if options.Tracer != nil {
+		options.Tracer.SearchDone(str, distance, stats)
+	}
+}
+
+// add sums the counters of other to the statistics, the peak queue size being the largest of both.
+func (stats *Stats) add(other Stats) {
+	stats.ItemsPushed += other.ItemsPushed
+	stats.NodesVisited += other.NodesVisited
+	if other.PeakQueueSize > stats.PeakQueueSize {
+		stats.PeakQueueSize = other.PeakQueueSize
+	}
+	stats.Results += other.Results
+	stats.Duration += other.Duration
+}
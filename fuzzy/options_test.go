@@ -0,0 +1,113 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"testing"
+)
+
+type testTracer struct {
+	calls int
+	str   string
+	stats Stats
+}
+
+func (tt *testTracer) SearchDone(str string, distance int, stats Stats) {
+	tt.calls++
+	tt.str = str
+	tt.stats = stats
+}
+
+func TestSearchStats(t *testing.T) {
+	testTrie := trie.New[string]()
+	word1 := "cat"
+	word2 := "dog"
+
+	testTrie.Insert(word1, &word1, trie.KeepLast[string])
+	testTrie.Insert(word2, &word2, trie.KeepLast[string])
+
+	var stats Stats
+	tracer := &testTracer{}
+	SearchWithOptions[string](context.Background(), testTrie, "cat", 0, NewListCollector[string](-1), Options[string]{
+		Stats:  &stats,
+		Tracer: tracer,
+	})
+
+	// root, c, ca, cat are expanded, and each one of them was pushed once
+	if stats.NodesVisited != 4 || stats.ItemsPushed != 4 || stats.PeakQueueSize != 1 || stats.Results != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if tracer.calls != 1 || tracer.str != "cat" || tracer.stats != stats {
+		t.Fatal("the tracer should of been called once with the stats of the search")
+	}
+
+	var parallelStats Stats
+	SearchWithOptions[string](context.Background(), testTrie, "cat", 3, NewListCollector[string](-1), Options[string]{
+		Workers: 2,
+		Stats:   &parallelStats,
+	})
+
+	if parallelStats.Results != 2 || parallelStats.NodesVisited == 0 || parallelStats.PeakQueueSize == 0 {
+		t.Fatalf("unexpected stats %+v", parallelStats)
+	}
+}
+
+func TestSearchBudget(t *testing.T) {
+	testTrie := trie.New[string]()
+	words := []string{"cat", "tat", "bat", "dog"}
+
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepLast[string])
+	}
+
+	collector := NewListCollector[string](-1)
+	status, _ := SearchWithOptions[string](context.Background(), testTrie, "cat", 0, collector, Options[string]{
+		MaxNodesVisited: 10,
+	})
+
+	if status != SearchSpaceExhausted || len(collector.Results) != 1 {
+		t.Fatal("the budget is large enough for an exact match")
+	}
+
+	var stats Stats
+	collector = NewListCollector[string](-1)
+	status, _ = SearchWithOptions[string](context.Background(), testTrie, "cat", 3, collector, Options[string]{
+		MaxNodesVisited: 4,
+		Stats:           &stats,
+	})
+
+	if status != BudgetExhausted || stats.NodesVisited != 4 {
+		t.Fatalf("the search should of stopped after visiting 4 nodes, visited %d", stats.NodesVisited)
+	}
+
+	if len(collector.Results) != 1 || *collector.Results[0].Value != "cat" {
+		t.Fatal("the exact match should of been found within the budget")
+	}
+
+	collector = NewListCollector[string](-1)
+	status, _ = SearchWithOptions[string](context.Background(), testTrie, "cat", 3, collector, Options[string]{
+		MaxQueueSize: 5,
+		Stats:        &stats,
+	})
+
+	if status != BudgetExhausted || stats.PeakQueueSize <= 5 {
+		t.Fatalf("the search should of stopped once the queue went over 5 items, peak was %d", stats.PeakQueueSize)
+	}
+
+	collector = NewListCollector[string](-1)
+	status, _ = SearchWithOptions[string](context.Background(), testTrie, "cat", 3, collector, Options[string]{
+		Workers:      2,
+		MaxQueueSize: 6,
+	})
+
+	if status != BudgetExhausted {
+		t.Fatal("the parallel search should of exhausted its budget")
+	}
+
+	for i := 1; i < len(collector.Results); i++ {
+		if collector.Results[i-1].Distance > collector.Results[i].Distance {
+			t.Fatal("partial results should still be ordered by distance")
+		}
+	}
+}
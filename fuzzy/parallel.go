@@ -0,0 +1,238 @@
+package fuzzy
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// parallelMessage is sent by the workers to the goroutine merging the results.
+// It either carries a match, tells that the worker collected all its matches at distance level,
+// or that the worker stopped because its budget was exhausted.
+type parallelMessage[T any] struct {
+	value           *T
+	distance        int
+	levelDone       bool
+	level           int
+	budgetExhausted bool
+}
+
+// searchParallel splits the children of the root between workers, each worker explores its own part of the trie.
+// The results are merged back on the calling goroutine: a match at distance d is only given to the collector once
+// every worker is done with the distances smaller than d, so the collector still sees the closest matches first.
+func searchParallel[N Node[N, T], T any](
+	ctx context.Context,
+	node N,
+	str string,
+	distance int,
+	collector ResultCollector[T],
+	options Options[T],
+) (Stats, Status) {
+	workers := options.Workers
+	var firstRunes []rune
+	node.Iterate(func(r rune, _ N) {
+		firstRunes = append(firstRunes, r)
+	})
+	// sort so that the partition does not depend on the map iteration order
+	sort.Slice(firstRunes, func(i, j int) bool {
+		return firstRunes[i] < firstRunes[j]
+	})
+
+	if workers > len(firstRunes) {
+		workers = len(firstRunes)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	owner := make(map[rune]int, len(firstRunes))
+	for i, r := range firstRunes {
+		owner[r] = i % workers
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages := make(chan parallelMessage[T], 4*workers)
+	runes := []rune(str)
+	seed := rootSeed(node, distance)
+	prune := pruneNodes[N, T](options.Prune)
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+
+	searchers := make([]*searcher[N, T], 0, workers)
+	for i := 0; i < workers; i++ {
+		workerId := i
+		s := &searcher[N, T]{
+			root:     node,
+			runes:    runes,
+			distance: distance,
+			owns: func(r rune) bool {
+				return owner[r] == workerId
+			},
+			// only one worker may return the value of the root
+			ownsRoot:        workerId == 0,
+			seed:            seed,
+			maxNodesVisited: splitBudget(options.MaxNodesVisited, workers),
+			maxQueueSize:    splitBudget(options.MaxQueueSize, workers),
+			filter:          options.Filter,
+			prune:           prune,
+			prefix:          options.Prefix,
+		}
+
+		searchers = append(searchers, s)
+		go parallelWorker[N, T](workerCtx, s, messages, &waitGroup)
+	}
+
+	// the workers do not close the channel, so we do it once they are all done
+	go func() {
+		waitGroup.Wait()
+		close(messages)
+	}()
+
+	results, status := mergeResults[T](ctx, messages, collector, distance, workers)
+
+	// stop the workers and wait until they are gone so that nothing runs after we return
+	cancel()
+	for range messages {
+	}
+
+	// the workers are done, their stats can safely be read
+	var stats Stats
+	for _, s := range searchers {
+		stats.ItemsPushed += s.stats.ItemsPushed
+		stats.NodesVisited += s.stats.NodesVisited
+		stats.PeakQueueSize += s.stats.PeakQueueSize
+	}
+	stats.Results = results
+
+	return stats, status
+}
+
+// splitBudget shares a budget between workers, a budget <= 0 means there's no limit.
+func splitBudget(budget int, workers int) int {
+	if budget <= 0 {
+		return budget
+	}
+
+	return (budget + workers - 1) / workers
+}
+
+func parallelWorker[N Node[N, T], T any](
+	ctx context.Context,
+	s *searcher[N, T],
+	messages chan<- parallelMessage[T],
+	waitGroup *sync.WaitGroup,
+) {
+	defer waitGroup.Done()
+
+	doneCh := ctx.Done()
+	canceled := false
+
+	send := func(message parallelMessage[T]) {
+		if canceled {
+			return
+		}
+
+		select {
+		case messages <- message:
+		case <-doneCh:
+			canceled = true
+		}
+	}
+
+	status := s.run(
+		ctx,
+		func(t *T, distance int) {
+			send(parallelMessage[T]{value: t, distance: distance})
+		},
+		func() bool {
+			return canceled
+		},
+		func(level int) {
+			send(parallelMessage[T]{levelDone: true, level: level})
+		},
+	)
+
+	if status == BudgetExhausted {
+		send(parallelMessage[T]{budgetExhausted: true})
+	}
+}
+
+// mergeResults gives the results of the workers to the collector by increasing distance.
+// It returns how many were given, and why it stopped.
+func mergeResults[T any](
+	ctx context.Context,
+	messages <-chan parallelMessage[T],
+	collector ResultCollector[T],
+	distance int,
+	workers int,
+) (int, Status) {
+	// pending holds the matches found at a distance above the current level
+	pending := make([][]*T, distance+1)
+	// workersDone counts, for every level, how many workers are done with it
+	workersDone := make([]int, distance+1)
+	level := 0
+	results := 0
+	doneCh := ctx.Done()
+
+	for !collector.Done() && level <= distance {
+		var message parallelMessage[T]
+		var ok bool
+
+		select {
+		case <-doneCh:
+			return results, Canceled
+		case message, ok = <-messages:
+		}
+
+		if !ok {
+			// the workers only stop early if the context got canceled
+			return results, Canceled
+		}
+
+		if message.budgetExhausted {
+			// the worker may not have found all its matches at the current level, so we can't go further
+			return results, BudgetExhausted
+		}
+
+		if !message.levelDone {
+			if message.distance == level {
+				collector.Collect(message.value, message.distance)
+				results++
+			} else {
+				pending[message.distance] = append(pending[message.distance], message.value)
+			}
+
+			continue
+		}
+
+		workersDone[message.level]++
+
+		// move on to the next levels as long as every worker is done with the current one
+		for level <= distance && workersDone[level] == workers {
+			level++
+			if level > distance {
+				break
+			}
+
+			for i, value := range pending[level] {
+				if collector.Done() {
+					return results, CollectorDone
+				}
+
+				collector.Collect(value, level)
+				results++
+				pending[level][i] = nil
+			}
+			pending[level] = nil
+		}
+	}
+
+	if collector.Done() {
+		return results, CollectorDone
+	}
+
+	return results, SearchSpaceExhausted
+}
@@ -0,0 +1,110 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+	"testing"
+)
+
+func TestSearchParallel(t *testing.T) {
+	words := []string{"", "cat", "tat", "dog", "cart", "card", "bat", "at", "catalog", "dot", "⌘at"}
+	testTrie := trie.New[string]()
+
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepFirst[string])
+	}
+
+	queries := []string{"cat", "at", "", "dgo", "catalgo", "xyz"}
+
+	for _, query := range queries {
+		for distance := 0; distance <= 3; distance++ {
+			expected := NewListCollector[string](-1)
+			Search[string](context.Background(), testTrie, query, distance, expected)
+
+			for workers := 2; workers <= 5; workers++ {
+				actual := NewListCollector[string](-1)
+				SearchWithOptions[string](context.Background(), testTrie, query, distance, actual, Options[string]{
+					Workers: workers,
+				})
+
+				checkSameResults(t, query, distance, expected.Results, actual.Results)
+			}
+		}
+	}
+}
+
+func TestSearchParallelStopsWhenCollectorIsDone(t *testing.T) {
+	words := []string{"cat", "tat", "bat", "rat", "mat", "dog"}
+	testTrie := trie.New[string]()
+
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepLast[string])
+	}
+
+	collector := NewListCollector[string](2)
+	SearchWithOptions[string](context.Background(), testTrie, "cat", 3, collector, Options[string]{
+		Workers: 3,
+	})
+
+	if len(collector.Results) != 2 {
+		t.Fatalf("expected 2 results but got %d", len(collector.Results))
+	}
+
+	if *collector.Results[0].Value != "cat" || collector.Results[0].Distance != 0 || collector.Results[1].Distance != 1 {
+		t.Fatalf("unexpected results %v", collector.Results)
+	}
+}
+
+func TestSearchParallelCanBeCanceled(t *testing.T) {
+	testTrie := trie.New[string]()
+	word := "cat"
+	testTrie.Insert(word, &word, trie.KeepLast[string])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	collector := NewListCollector[string](-1)
+	SearchWithOptions[string](ctx, testTrie, "cat", 1, collector, Options[string]{
+		Workers: 2,
+	})
+
+	if len(collector.Results) != 0 {
+		t.Fatal("a canceled search should not return any result")
+	}
+}
+
+// checkSameResults verifies both lists contain the same values with the same distances,
+// and that actual is ordered by distance. The order between results at the same distance is not defined.
+func checkSameResults(t *testing.T, query string, distance int, expected []Result[string], actual []Result[string]) {
+	for i := 1; i < len(actual); i++ {
+		if actual[i-1].Distance > actual[i].Distance {
+			t.Fatalf("query '%s' with distance %d: results are not ordered by distance", query, distance)
+		}
+	}
+
+	sortResults := func(results []Result[string]) []Result[string] {
+		out := append([]Result[string](nil), results...)
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].Distance != out[j].Distance {
+				return out[i].Distance < out[j].Distance
+			}
+
+			return *out[i].Value < *out[j].Value
+		})
+		return out
+	}
+
+	sortedExpected := sortResults(expected)
+	sortedActual := sortResults(actual)
+
+	if len(sortedExpected) != len(sortedActual) {
+		t.Fatalf("query '%s' with distance %d: expected %d results but got %d", query, distance, len(sortedExpected), len(sortedActual))
+	}
+
+	for i := range sortedExpected {
+		if sortedExpected[i].Value != sortedActual[i].Value || sortedExpected[i].Distance != sortedActual[i].Distance {
+			t.Fatalf("query '%s' with distance %d: unexpected result at position %d", query, distance, i)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"testing"
+)
+
+func TestSearchPrefix(t *testing.T) {
+	words := []string{"ber", "bern", "berne", "berlin", "bernay", "basel", "paris"}
+	testTrie := trie.New[string]()
+
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepLast[string])
+	}
+
+	for _, workers := range []int{1, 2} {
+		collector := NewListCollector[string](-1)
+		SearchWithOptions[string](context.Background(), testTrie, "bern", 0, collector, Options[string]{
+			Workers: workers,
+			Prefix:  true,
+		})
+
+		checkSameResults(t, "bern", 0, []Result[string]{
+			{Value: &words[1], Distance: 0},
+			{Value: &words[2], Distance: 0},
+			{Value: &words[4], Distance: 0},
+		}, collector.Results)
+
+		collector = NewListCollector[string](-1)
+		SearchWithOptions[string](context.Background(), testTrie, "brel", 1, collector, Options[string]{
+			Workers: workers,
+			Prefix:  true,
+		})
+
+		checkSameResults(t, "brel", 1, []Result[string]{
+			{Value: &words[3], Distance: 1},
+		}, collector.Results)
+
+		collector = NewListCollector[string](-1)
+		SearchWithOptions[string](context.Background(), testTrie, "bex", 1, collector, Options[string]{
+			Workers: workers,
+			Prefix:  true,
+		})
+
+		checkSameResults(t, "bex", 1, []Result[string]{
+			{Value: &words[0], Distance: 1},
+			{Value: &words[1], Distance: 1},
+			{Value: &words[2], Distance: 1},
+			{Value: &words[3], Distance: 1},
+			{Value: &words[4], Distance: 1},
+		}, collector.Results)
+
+		collector = NewListCollector[string](-1)
+		SearchWithOptions[string](context.Background(), testTrie, "", 0, collector, Options[string]{
+			Workers: workers,
+			Prefix:  true,
+		})
+
+		if len(collector.Results) != len(words) {
+			t.Fatalf("an empty prefix should match all the words, got %d", len(collector.Results))
+		}
+	}
+
+	collector := NewListCollector[string](2)
+	SearchWithOptions[string](context.Background(), testTrie, "b", 0, collector, Options[string]{
+		Prefix: true,
+	})
+
+	if len(collector.Results) != 2 {
+		t.Fatal("the search should stop once the collector is done")
+	}
+}
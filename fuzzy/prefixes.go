@@ -0,0 +1,235 @@
+package fuzzy
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/internal/queue"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+	"time"
+)
+
+// PrefixMatch is a key of the trie matching the beginning of a string.
+type PrefixMatch[T any] struct {
+	Value *T
+	// Length is the number of runes of the string matched by the key
+	Length int
+	// Distance is the number of edits between the key and the first Length runes of the string
+	Distance int
+}
+
+// MatchPrefixes returns the keys of the trie within the distance of a prefix of str, it's the fuzzy version of
+// trie.AllPrefixes. A key can match several prefixes of str, every prefix is returned with its smallest distance.
+// The matches are sorted by increasing distance, and then by decreasing length. The matches with the same distance
+// and length always come in the same order, the trie being explored by increasing rune.
+func MatchPrefixes[T any](ctx context.Context, node *trie.Trie[T], str string, distance int) ([]PrefixMatch[T], error) {
+	matches, _, err := MatchPrefixesWithOptions[T](ctx, node, str, distance, Options[T]{})
+	return matches, err
+}
+
+// MatchPrefixesWithOptions behaves like MatchPrefixes, the budgets, statistics and hooks of the options are used the
+// same way as SearchWithOptions does, but Options.Workers and Options.Prefix are ignored.
+// It returns why the search stopped, if the budget got exhausted the matches found so far are returned but some may
+// be missing.
+func MatchPrefixesWithOptions[T any](
+	ctx context.Context,
+	node *trie.Trie[T],
+	str string,
+	distance int,
+	options Options[T],
+) ([]PrefixMatch[T], Status, error) {
+	startTime := time.Now()
+	s := &searcher[*trie.Trie[T], T]{
+		root:            node,
+		runes:           []rune(str),
+		distance:        distance,
+		ownsRoot:        true,
+		seed:            rootSeed(node, distance),
+		maxNodesVisited: options.MaxNodesVisited,
+		maxQueueSize:    options.MaxQueueSize,
+		filter:          options.Filter,
+		prune:           options.Prune,
+		// the callers pick the first of the matches with the same distance and length, it must not be random
+		sortChildren: true,
+	}
+
+	matches, status := s.matchPrefixes(ctx)
+	options.reportStats(str, distance, s.stats, startTime)
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+
+		return matches[i].Length > matches[j].Length
+	})
+
+	if status == Canceled {
+		return matches, status, ctx.Err()
+	}
+
+	return matches, status, nil
+}
+
+// matchPrefixes explores the trie like run, but collects the values at every position of the string.
+func (s *searcher[N, T]) matchPrefixes(ctx context.Context) ([]PrefixMatch[T], Status) {
+	type visitKey struct {
+		step     N
+		position int
+	}
+
+	priorityQueue := queue.New[N]()
+	push := s.pusher(priorityQueue)
+	for _, item := range s.seed {
+		push(item)
+	}
+
+	// the items are popped by decreasing number of errors left, so the first visit of a state is always the best one
+	visited := make(map[visitKey]struct{})
+	var matches []PrefixMatch[T]
+	doneCh := ctx.Done()
+
+	for crtItem := priorityQueue.Pop(); crtItem != nil; crtItem = priorityQueue.Pop() {
+		select {
+		case <-doneCh:
+			return matches, Canceled
+		default:
+		}
+
+		key := visitKey{step: crtItem.Step, position: crtItem.Position}
+		if _, ok := visited[key]; ok {
+			continue
+		}
+		visited[key] = struct{}{}
+
+		if s.budgetExhausted() {
+			return matches, BudgetExhausted
+		}
+
+		s.stats.NodesVisited++
+
+		if value := crtItem.Step.GetValue(); value != nil && (s.filter == nil || s.filter(value)) {
+			matches = append(matches, PrefixMatch[T]{
+				Value:    value,
+				Length:   crtItem.Position,
+				Distance: s.distance - crtItem.ErrorsLeft,
+			})
+			s.stats.Results++
+		}
+
+		s.expand(crtItem, push)
+	}
+
+	return matches, SearchSpaceExhausted
+}
+
+// Segment is a part of a text matched by a key of the trie.
+type Segment[T any] struct {
+	Value *T
+	// Start and End are the positions in runes of the segment in the text
+	Start int
+	End   int
+	// Distance is the number of edits between the key and the segment
+	Distance int
+}
+
+// SegmentText splits the text into keys of the trie, allowing up to distance edits per key. Among all the ways of
+// splitting the text, it picks the one with the smallest total distance, and then with the fewest segments.
+// It returns nil if the text can't be split.
+func SegmentText[T any](ctx context.Context, node *trie.Trie[T], text string, distance int) ([]Segment[T], error) {
+	segments, _, err := SegmentTextWithOptions[T](ctx, node, text, distance, Options[T]{})
+	return segments, err
+}
+
+// SegmentTextWithOptions behaves like SegmentText, the options are used by the MatchPrefixesWithOptions search run at
+// every position of the text, so the budgets apply to each of them. Options.Stats sums the statistics of all the
+// searches, while Options.Tracer is notified after every one of them.
+// It returns BudgetExhausted if any of the searches ran out of budget, the text may then be split in a worse way or
+// not at all.
+func SegmentTextWithOptions[T any](
+	ctx context.Context,
+	node *trie.Trie[T],
+	text string,
+	distance int,
+	options Options[T],
+) ([]Segment[T], Status, error) {
+	type split struct {
+		reachable bool
+		distance  int
+		segments  int
+		// last is the segment ending at this position
+		last Segment[T]
+	}
+
+	runes := []rune(text)
+	// best[i] is the best way of splitting the first i runes
+	best := make([]split, len(runes)+1)
+	best[0].reachable = true
+
+	status := SearchSpaceExhausted
+	var totalStats Stats
+
+	for start := 0; start < len(runes); start++ {
+		if !best[start].reachable {
+			continue
+		}
+
+		var searchStats Stats
+		searchOptions := options
+		searchOptions.Stats = &searchStats
+
+		matches, searchStatus, err := MatchPrefixesWithOptions[T](ctx, node, string(runes[start:]), distance, searchOptions)
+		totalStats.add(searchStats)
+		if err != nil {
+			if options.Stats != nil {
+				*options.Stats = totalStats
+			}
+			return nil, searchStatus, err
+		}
+
+		if searchStatus == BudgetExhausted {
+			status = BudgetExhausted
+		}
+
+		for _, match := range matches {
+			if match.Length == 0 {
+				// an empty segment does not split anything
+				continue
+			}
+
+			end := start + match.Length
+			candidate := split{
+				reachable: true,
+				distance:  best[start].distance + match.Distance,
+				segments:  best[start].segments + 1,
+				last: Segment[T]{
+					Value:    match.Value,
+					Start:    start,
+					End:      end,
+					Distance: match.Distance,
+				},
+			}
+
+			crt := best[end]
+			if !crt.reachable || candidate.distance < crt.distance ||
+				(candidate.distance == crt.distance && candidate.segments < crt.segments) {
+				best[end] = candidate
+			}
+		}
+	}
+
+	if options.Stats != nil {
+		*options.Stats = totalStats
+	}
+
+	if !best[len(runes)].reachable {
+		return nil, status, nil
+	}
+
+	segments := make([]Segment[T], best[len(runes)].segments)
+	for end, i := len(runes), len(segments)-1; i >= 0; i-- {
+		segments[i] = best[end].last
+		end = best[end].last.Start
+	}
+
+	return segments, status, nil
+}
@@ -0,0 +1,140 @@
+package fuzzy
+
+import (
+	"context"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"strings"
+	"testing"
+)
+
+func newPrefixesTestTrie(words ...string) *trie.Trie[string] {
+	testTrie := trie.New[string]()
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepFirst[string])
+	}
+
+	return testTrie
+}
+
+func TestMatchPrefixes(t *testing.T) {
+	testTrie := newPrefixesTestTrie("new", "new york", "york", "newark")
+
+	matches, err := MatchPrefixes[string](context.Background(), testTrie, "new yrok city", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []string
+	for _, match := range matches {
+		results = append(results, fmt.Sprintf("%s:%d:%d", *match.Value, match.Length, match.Distance))
+	}
+
+	// new matches 'new' exactly, 'ne' and 'new ' with one edit, and new york matches 'new yrok' with a swap
+	expected := "new:3:0|new york:8:1|new:4:1|new:2:1"
+	if strings.Join(results, "|") != expected {
+		t.Fatalf("expected %s but got %s", expected, strings.Join(results, "|"))
+	}
+
+	matches, err = MatchPrefixes[string](context.Background(), testTrie, "paris", 1)
+	if err != nil || len(matches) != 0 {
+		t.Fatalf("there should be no match, got %v", matches)
+	}
+}
+
+func TestMatchPrefixesCanBeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := MatchPrefixes[string](ctx, newPrefixesTestTrie("new"), "new", 1)
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got %v", err)
+	}
+}
+
+func TestMatchPrefixesWithOptions(t *testing.T) {
+	testTrie := newPrefixesTestTrie("new", "new york", "york", "newark")
+
+	var stats Stats
+	matches, status, err := MatchPrefixesWithOptions[string](context.Background(), testTrie, "new yrok city", 1, Options[string]{
+		Filter: func(key *string) bool {
+			return *key != "new"
+		},
+		Stats: &stats,
+	})
+	if err != nil || status != SearchSpaceExhausted {
+		t.Fatalf("unexpected status %s and error %v", status, err)
+	}
+
+	if len(matches) != 1 || *matches[0].Value != "new york" || stats.Results != 1 || stats.NodesVisited == 0 {
+		t.Fatalf("unexpected matches %v with stats %+v", matches, stats)
+	}
+
+	_, status, err = MatchPrefixesWithOptions[string](context.Background(), testTrie, "new yrok city", 1, Options[string]{
+		MaxNodesVisited: 5,
+		Stats:           &stats,
+	})
+	if err != nil || status != BudgetExhausted || stats.NodesVisited != 5 {
+		t.Fatalf("the budget should of been exhausted, got %s after %d nodes", status, stats.NodesVisited)
+	}
+}
+
+func TestSegmentText(t *testing.T) {
+	testTrie := newPrefixesTestTrie("new", "york", "newyork", "city", "cat", "ci")
+
+	for _, test := range []struct {
+		text     string
+		distance int
+		expected string
+	}{
+		{text: "newyorkcity", distance: 0, expected: "newyork:0|city:0"},
+		{text: "newyrokcity", distance: 1, expected: "newyork:1|city:0"},
+		{text: "newyorkcty", distance: 1, expected: "newyork:0|city:1"},
+		{text: "newyorkcty", distance: 0, expected: ""},
+		{text: "", distance: 0, expected: ""},
+	} {
+		segments, err := SegmentText[string](context.Background(), testTrie, test.text, test.distance)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var results []string
+		end := 0
+		for _, segment := range segments {
+			if segment.Start != end {
+				t.Fatalf("the segments of %s should be contiguous", test.text)
+			}
+			end = segment.End
+
+			results = append(results, fmt.Sprintf("%s:%d", *segment.Value, segment.Distance))
+		}
+
+		if strings.Join(results, "|") != test.expected {
+			t.Fatalf("expected %s for %s but got %s", test.expected, test.text, strings.Join(results, "|"))
+		}
+	}
+}
+
+func TestSegmentTextWithOptions(t *testing.T) {
+	testTrie := newPrefixesTestTrie("new", "york", "city")
+
+	var stats Stats
+	segments, status, err := SegmentTextWithOptions[string](context.Background(), testTrie, "newyorkcity", 0, Options[string]{
+		Stats: &stats,
+	})
+	if err != nil || status != SearchSpaceExhausted || len(segments) != 3 {
+		t.Fatalf("unexpected segments %v with status %s and error %v", segments, status, err)
+	}
+
+	// the statistics of the three searches are summed
+	if stats.Results != 3 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	segments, status, err = SegmentTextWithOptions[string](context.Background(), testTrie, "newyorkcity", 0, Options[string]{
+		MaxNodesVisited: 2,
+	})
+	if err != nil || status != BudgetExhausted || segments != nil {
+		t.Fatalf("the budget should of been exhausted, got %v with status %s", segments, status)
+	}
+}
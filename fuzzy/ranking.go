@@ -0,0 +1,141 @@
+package fuzzy
+
+import (
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/distance"
+)
+
+// Ranking tells how the results with the same distance are sorted by a RankingCollector.
+type Ranking int
+
+const (
+	// ByDistance keeps the results with the same distance in the order the search found them.
+	ByDistance Ranking = iota
+	// ByJaroWinkler sorts the ties by decreasing Jaro-Winkler similarity with the query, see distance.JaroWinkler.
+	ByJaroWinkler
+	// ByCommonPrefix sorts the ties by decreasing length of the prefix they share with the query.
+	ByCommonPrefix
+	// ByBigramDice sorts the ties by decreasing number of bigrams they share with the query, see distance.BigramDice.
+	ByBigramDice
+)
+
+// ParseRanking returns the ranking with the name returned by Ranking.String.
+func ParseRanking(name string) (Ranking, error) {
+	for _, ranking := range []Ranking{ByDistance, ByJaroWinkler, ByCommonPrefix, ByBigramDice} {
+		if ranking.String() == name {
+			return ranking, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown ranking '%s'", name)
+}
+
+func (r Ranking) String() string {
+	switch r {
+	case ByDistance:
+		return "distance"
+	case ByJaroWinkler:
+		return "jaro-winkler"
+	case ByCommonPrefix:
+		return "common-prefix"
+	case ByBigramDice:
+		return "bigram-dice"
+	}
+
+	return "unknown"
+}
+
+// Similarity returns the similarity between the query and a key used to sort the ties, the higher the closer.
+// It's nil for ByDistance.
+func (r Ranking) Similarity() func(query string, key string) float64 {
+	switch r {
+	case ByJaroWinkler:
+		return distance.JaroWinkler
+	case ByCommonPrefix:
+		return func(query string, key string) float64 {
+			return float64(distance.CommonPrefix(query, key))
+		}
+	case ByBigramDice:
+		return distance.BigramDice
+	}
+
+	return nil
+}
+
+// RankingCollector collects the results like a ListCollector, but the results with the same distance are sorted by
+// decreasing similarity between the query and their key, the ties being kept in the order of the search.
+// The values don't hold their key, so Key has to return it.
+// To rank all the results at the distance of the last one, the collector is only done once the search moves on to
+// the next distance, so it may explore more of the trie than a ListCollector.
+type RankingCollector[T any] struct {
+	// MaxResult is the number of results to collect, if < 0 all the results are collected
+	MaxResult int
+	Results   []Result[T]
+	Query     string
+	Key       func(t *T) string
+	Ranking   Ranking
+	// scores holds the similarities of the results
+	scores     []float64
+	similarity func(query string, key string) float64
+	done       bool
+}
+
+func NewRankingCollector[T any](maxResult int, query string, key func(t *T) string, ranking Ranking) *RankingCollector[T] {
+	return &RankingCollector[T]{
+		MaxResult:  maxResult,
+		Results:    make([]Result[T], 0, 0),
+		Query:      query,
+		Key:        key,
+		Ranking:    ranking,
+		similarity: ranking.Similarity(),
+	}
+}
+
+func (rc *RankingCollector[T]) Collect(t *T, distance int) {
+	if t == nil || rc.done {
+		return
+	}
+
+	full := rc.MaxResult >= 0 && len(rc.Results) >= rc.MaxResult
+	if full && distance > rc.Results[len(rc.Results)-1].Distance {
+		// the results come by increasing distance, so the last distance is complete
+		rc.done = true
+		return
+	}
+
+	if rc.similarity == nil {
+		// there's nothing to rank, it behaves like a ListCollector
+		rc.Results = append(rc.Results, Result[T]{Value: t, Distance: distance})
+		rc.done = rc.MaxResult >= 0 && len(rc.Results) >= rc.MaxResult
+		return
+	}
+
+	score := rc.similarity(rc.Query, rc.Key(t))
+
+	// the result goes after the ones that are closer or as close to the query
+	position := len(rc.Results)
+	for position > 0 && rc.Results[position-1].Distance == distance && rc.scores[position-1] < score {
+		position--
+	}
+
+	if full && position == len(rc.Results) {
+		// it would be dropped right away
+		return
+	}
+
+	rc.Results = append(rc.Results, Result[T]{})
+	rc.scores = append(rc.scores, 0)
+	copy(rc.Results[position+1:], rc.Results[position:])
+	copy(rc.scores[position+1:], rc.scores[position:])
+	rc.Results[position] = Result[T]{Value: t, Distance: distance}
+	rc.scores[position] = score
+
+	if full {
+		rc.Results = rc.Results[:rc.MaxResult]
+		rc.scores = rc.scores[:rc.MaxResult]
+	}
+}
+
+func (rc *RankingCollector[T]) Done() bool {
+	return rc.done || rc.MaxResult == 0
+}
@@ -0,0 +1,80 @@
+package fuzzy
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func stringKey(s *string) string {
+	return *s
+}
+
+func rankedKeys(results []Result[string]) string {
+	var keys []string
+	for _, result := range results {
+		keys = append(keys, *result.Value)
+	}
+
+	return strings.Join(keys, " ")
+}
+
+func TestRankingCollector(t *testing.T) {
+	testTrie := newPrefixesTestTrie("fern", "born", "berne", "bern", "bearn", "zurich")
+
+	tests := []struct {
+		ranking   Ranking
+		maxResult int
+		expected  string
+	}{
+		{ByJaroWinkler, -1, "bern berne bearn born fern"},
+		{ByCommonPrefix, -1, "bern berne bearn born fern"},
+		{ByBigramDice, -1, "bern berne fern bearn born"},
+		// the ties are all ranked before the results are cut
+		{ByJaroWinkler, 2, "bern berne"},
+		{ByBigramDice, 3, "bern berne fern"},
+		{ByJaroWinkler, 0, ""},
+	}
+
+	for _, test := range tests {
+		collector := NewRankingCollector[string](test.maxResult, "bern", stringKey, test.ranking)
+		Search[string](context.Background(), testTrie, "bern", 1, collector)
+
+		if keys := rankedKeys(collector.Results); keys != test.expected {
+			t.Errorf("%s with %d results: expected '%s', got '%s'", test.ranking, test.maxResult, test.expected, keys)
+		}
+
+		for i := 1; i < len(collector.Results); i++ {
+			if collector.Results[i].Distance < collector.Results[i-1].Distance {
+				t.Fatalf("%s: the results should still be sorted by distance", test.ranking)
+			}
+		}
+	}
+}
+
+func TestRankingCollectorByDistance(t *testing.T) {
+	testTrie := newPrefixesTestTrie("fern", "born", "berne", "bern")
+
+	ranked := NewRankingCollector[string](3, "bern", stringKey, ByDistance)
+	Search[string](context.Background(), testTrie, "bern", 1, ranked)
+
+	list := NewListCollector[string](3)
+	Search[string](context.Background(), testTrie, "bern", 1, list)
+
+	if rankedKeys(ranked.Results) != rankedKeys(list.Results) {
+		t.Fatalf("expected the results of a ListCollector '%s', got '%s'", rankedKeys(list.Results), rankedKeys(ranked.Results))
+	}
+}
+
+func TestParseRanking(t *testing.T) {
+	for _, ranking := range []Ranking{ByDistance, ByJaroWinkler, ByCommonPrefix, ByBigramDice} {
+		parsed, err := ParseRanking(ranking.String())
+		if err != nil || parsed != ranking {
+			t.Fatalf("could not parse %s", ranking)
+		}
+	}
+
+	if _, err := ParseRanking("soundex"); err == nil {
+		t.Fatal("soundex is not a ranking")
+	}
+}
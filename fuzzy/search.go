@@ -2,101 +2,336 @@ package fuzzy
 
 import (
 	"context"
-	"github.com/marcadamsge/gofuzzy/queue"
+	"github.com/marcadamsge/gofuzzy/internal/queue"
 	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+	"time"
 )
 
 // Search a fuzzy match on the trie until collector.Done() is true or there is no more match given the Levenshtein distance.
 // Search calls collector.Collect first with the closest match, and then the second closest, etc...
 func Search[T any](ctx context.Context, node *trie.Trie[T], str string, distance int, collector ResultCollector[T]) {
-	priorityQueue := queue.New[T]()
-	priorityQueue.Add(&queue.Item[T]{
-		Position:   0,
-		Step:       node,
-		ErrorsLeft: distance,
-	})
+	SearchWithOptions[T](ctx, node, str, distance, collector, Options[T]{})
+}
 
-	runes := []rune(str)
-	resultSet := make(map[*trie.Trie[T]]struct{})
-	maxPosition := len(runes)
+// SearchWithOptions behaves like Search, the options let the caller tune how the trie is explored.
+// It returns why the search stopped, and ctx.Err() if that's because the context got canceled.
+func SearchWithOptions[T any](
+	ctx context.Context,
+	node *trie.Trie[T],
+	str string,
+	distance int,
+	collector ResultCollector[T],
+	options Options[T],
+) (Status, error) {
+	return SearchNode[*trie.Trie[T], T](ctx, node, str, distance, collector, options)
+}
+
+// SearchNode behaves like SearchWithOptions, but explores any structure implementing Node, like a dawg.DAWG.
+// Options.Prune is only used if the nodes are tries.
+func SearchNode[N Node[N, T], T any](
+	ctx context.Context,
+	node N,
+	str string,
+	distance int,
+	collector ResultCollector[T],
+	options Options[T],
+) (Status, error) {
+	startTime := time.Now()
+	var status Status
+	var stats Stats
+
+	if options.Workers > 1 && distance >= 0 {
+		stats, status = searchParallel[N, T](ctx, node, str, distance, collector, options)
+	} else {
+		s := &searcher[N, T]{
+			root:            node,
+			runes:           []rune(str),
+			distance:        distance,
+			ownsRoot:        true,
+			seed:            rootSeed(node, distance),
+			maxNodesVisited: options.MaxNodesVisited,
+			maxQueueSize:    options.MaxQueueSize,
+			filter:          options.Filter,
+			prune:           pruneNodes[N, T](options.Prune),
+			prefix:          options.Prefix,
+		}
+		status = s.run(ctx, collector.Collect, collector.Done, nil)
+		stats = s.stats
+	}
+
+	options.reportStats(str, distance, stats, startTime)
+
+	if status == Canceled {
+		return status, ctx.Err()
+	}
+
+	return status, nil
+}
+
+// rootSeed starts the exploration from the root of the trie.
+func rootSeed[N any](node N, distance int) []*queue.Item[N] {
+	return []*queue.Item[N]{
+		{
+			Position:   0,
+			Step:       node,
+			ErrorsLeft: distance,
+		},
+	}
+}
+
+// searcher holds the state of a single exploration of the trie.
+type searcher[N Node[N, T], T any] struct {
+	root     N
+	runes    []rune
+	distance int
+	// owns tells if the child of the root reached with the rune r should be explored,
+	// if nil all the children are explored.
+	owns func(r rune) bool
+	// ownsRoot tells if the value of the root itself can be collected.
+	ownsRoot bool
+	// seed holds the items the exploration starts from.
+	seed []*queue.Item[N]
+	// stats counts the work done by run.
+	stats Stats
+	// maxNodesVisited and maxQueueSize stop the exploration once they are reached, they're ignored if <= 0.
+	maxNodesVisited int
+	maxQueueSize    int
+	// filter and prune are the Options.Filter and Options.Prune hooks, they're ignored if nil.
+	filter func(t *T) bool
+	prune  func(node N) bool
+	// prefix collects all the values of the subtree of a match instead of the value of the match only.
+	prefix bool
+	// sortChildren explores the children of the nodes by increasing rune, so that the order of the matches with the
+	// same distance doesn't depend on the iteration order of the maps.
+	sortChildren bool
+}
+
+// budgetExhausted tells if the exploration went over one of its limits.
+func (s *searcher[N, T]) budgetExhausted() bool {
+	return (s.maxNodesVisited > 0 && s.stats.NodesVisited >= s.maxNodesVisited) ||
+		(s.maxQueueSize > 0 && s.stats.PeakQueueSize > s.maxQueueSize)
+}
+
+// stepsFrom tells if the trie can be stepped out from step with the rune r.
+func (s *searcher[N, T]) stepsFrom(step N, r rune) bool {
+	return s.owns == nil || step != s.root || s.owns(r)
+}
+
+// run explores the trie until done returns true or there is no more match, and returns why it stopped.
+// levelDone is called once for every distance from 0 to s.distance when all the matches at that distance
+// have been collected, it may be nil.
+func (s *searcher[N, T]) run(
+	ctx context.Context,
+	collect func(t *T, distance int),
+	done func() bool,
+	levelDone func(level int),
+) Status {
+	priorityQueue := queue.New[N]()
+	push := s.pusher(priorityQueue)
+
+	for _, item := range s.seed {
+		push(item)
+	}
+
+	resultSet := make(map[N]struct{})
+	maxPosition := len(s.runes)
+	level := 0
 
 	doneCh := ctx.Done()
 
-Loop:
-	for crtItem := priorityQueue.Pop(); crtItem != nil && !collector.Done(); crtItem = priorityQueue.Pop() {
+	for crtItem := priorityQueue.Pop(); crtItem != nil && !done(); crtItem = priorityQueue.Pop() {
 		// stop the loop of the context gets canceled
 		select {
 		case <-doneCh:
-			break Loop
+			return Canceled
 		default:
 		}
 
-		if crtItem.ErrorsLeft > 0 && maxPosition > crtItem.Position {
-			// a character was randomly changed with another one
-			crtItem.Step.Iterate(func(r rune, trie *trie.Trie[T]) {
-				if r != runes[crtItem.Position] {
-					priorityQueue.Add(&queue.Item[T]{
-						Position:   crtItem.Position + 1,
-						Step:       trie,
-						ErrorsLeft: crtItem.ErrorsLeft - 1,
-					})
-				}
-			})
-
-			// a character was inserted but shouldn't be there
-			priorityQueue.Add(&queue.Item[T]{
-				Position:   crtItem.Position + 1,
-				Step:       crtItem.Step,
-				ErrorsLeft: crtItem.ErrorsLeft - 1,
-			})
+		if s.budgetExhausted() {
+			return BudgetExhausted
 		}
 
-		// a character was removed
-		if crtItem.ErrorsLeft > 0 {
-			crtItem.Step.Iterate(func(r rune, trie *trie.Trie[T]) {
-				priorityQueue.Add(&queue.Item[T]{
-					Position:   crtItem.Position,
-					Step:       trie,
-					ErrorsLeft: crtItem.ErrorsLeft - 1,
-				})
-			})
-		}
+		s.stats.NodesVisited++
 
-		// two adjacent characters were swapped
-		if crtItem.ErrorsLeft > 0 && maxPosition-1 > crtItem.Position {
-			step1 := crtItem.Step.Step(runes[crtItem.Position+1])
-			if step1 != nil {
-				step2 := step1.Step(runes[crtItem.Position])
-				if step2 != nil {
-					priorityQueue.Add(&queue.Item[T]{
-						Position:   crtItem.Position + 2,
-						Step:       step2,
-						ErrorsLeft: crtItem.ErrorsLeft - 1,
-					})
-				}
-			}
+		// items are popped by increasing distance, so every level below this one is complete
+		for ; levelDone != nil && level < s.distance-crtItem.ErrorsLeft; level++ {
+			levelDone(level)
 		}
 
 		// test if we're in a final state
-		if maxPosition == crtItem.Position && crtItem.Step.Value != nil {
+		if maxPosition == crtItem.Position && s.prefix {
+			s.collectSubtree(crtItem.Step, s.distance-crtItem.ErrorsLeft, resultSet, collect, done)
+		} else if maxPosition == crtItem.Position && (s.ownsRoot || crtItem.Step != s.root) {
 			_, resultAlreadyReturned := resultSet[crtItem.Step]
 
 			if !resultAlreadyReturned {
-				collector.Collect(crtItem.Step.Value, distance-crtItem.ErrorsLeft)
-				resultSet[crtItem.Step] = struct{}{}
+				if value := crtItem.Step.GetValue(); value != nil {
+					if s.filter == nil || s.filter(value) {
+						collect(value, s.distance-crtItem.ErrorsLeft)
+						s.stats.Results++
+					}
+					resultSet[crtItem.Step] = struct{}{}
+				}
 			}
 		}
 
-		// try stepping out once
-		if maxPosition > crtItem.Position {
-			nextItem := crtItem.Step.Step(runes[crtItem.Position])
-			if nextItem != nil {
-				priorityQueue.Add(&queue.Item[T]{
+		s.expand(crtItem, push)
+	}
+
+	// the queue was either emptied, or the collector is done, in both cases no more results will come
+	for ; levelDone != nil && level <= s.distance; level++ {
+		levelDone(level)
+	}
+
+	if done() {
+		return CollectorDone
+	}
+
+	return SearchSpaceExhausted
+}
+
+// pusher returns the function adding the items to the queue, it skips the pruned nodes and counts the items.
+func (s *searcher[N, T]) pusher(priorityQueue *queue.PriorityQueue[N]) func(item *queue.Item[N]) {
+	return func(item *queue.Item[N]) {
+		if s.prune != nil && s.prune(item.Step) {
+			// nothing in this subtree can match
+			return
+		}
+
+		priorityQueue.Add(item)
+		s.stats.ItemsPushed++
+		if priorityQueue.Len() > s.stats.PeakQueueSize {
+			s.stats.PeakQueueSize = priorityQueue.Len()
+		}
+	}
+}
+
+// expand pushes the items following crtItem: the ones reached by making one more edit if there are errors left, and
+// the one reached by reading the next rune of s.runes. It's the only place the edits are defined, every exploration
+// of the trie builds on it and only decides what to collect and which items to skip.
+func (s *searcher[N, T]) expand(crtItem *queue.Item[N], push func(item *queue.Item[N])) {
+	runes := s.runes
+	maxPosition := len(runes)
+	// none is returned by Step if there's no child with the rune
+	var none N
+
+	if crtItem.ErrorsLeft > 0 && maxPosition > crtItem.Position {
+		// a character was randomly changed with another one
+		s.iterate(crtItem.Step, func(r rune, child N) {
+			if r != runes[crtItem.Position] && s.stepsFrom(crtItem.Step, r) {
+				push(&queue.Item[N]{
 					Position:   crtItem.Position + 1,
-					Step:       nextItem,
-					ErrorsLeft: crtItem.ErrorsLeft,
+					Step:       child,
+					ErrorsLeft: crtItem.ErrorsLeft - 1,
+				})
+			}
+		})
+
+		// a character was inserted but shouldn't be there
+		push(&queue.Item[N]{
+			Position:   crtItem.Position + 1,
+			Step:       crtItem.Step,
+			ErrorsLeft: crtItem.ErrorsLeft - 1,
+		})
+	}
+
+	// a character was removed
+	if crtItem.ErrorsLeft > 0 {
+		s.iterate(crtItem.Step, func(r rune, child N) {
+			if s.stepsFrom(crtItem.Step, r) {
+				push(&queue.Item[N]{
+					Position:   crtItem.Position,
+					Step:       child,
+					ErrorsLeft: crtItem.ErrorsLeft - 1,
+				})
+			}
+		})
+	}
+
+	// two adjacent characters were swapped
+	if crtItem.ErrorsLeft > 0 && maxPosition-1 > crtItem.Position && s.stepsFrom(crtItem.Step, runes[crtItem.Position+1]) {
+		step1 := crtItem.Step.Step(runes[crtItem.Position+1])
+		if step1 != none {
+			step2 := step1.Step(runes[crtItem.Position])
+			if step2 != none {
+				push(&queue.Item[N]{
+					Position:   crtItem.Position + 2,
+					Step:       step2,
+					ErrorsLeft: crtItem.ErrorsLeft - 1,
 				})
 			}
 		}
 	}
+
+	// try stepping out once
+	if maxPosition > crtItem.Position && s.stepsFrom(crtItem.Step, runes[crtItem.Position]) {
+		nextItem := crtItem.Step.Step(runes[crtItem.Position])
+		if nextItem != none {
+			push(&queue.Item[N]{
+				Position:   crtItem.Position + 1,
+				Step:       nextItem,
+				ErrorsLeft: crtItem.ErrorsLeft,
+			})
+		}
+	}
+}
+
+// iterate calls iterationFunction with every child of step, by increasing rune if s.sortChildren is set.
+func (s *searcher[N, T]) iterate(step N, iterationFunction func(r rune, child N)) {
+	if !s.sortChildren {
+		step.Iterate(iterationFunction)
+		return
+	}
+
+	type runeChild struct {
+		r     rune
+		child N
+	}
+
+	var children []runeChild
+	step.Iterate(func(r rune, child N) {
+		children = append(children, runeChild{r: r, child: child})
+	})
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].r < children[j].r
+	})
+
+	for _, c := range children {
+		iterationFunction(c.r, c.child)
+	}
+}
+
+// collectSubtree collects all the values in the subtree of step with the same distance.
+// In prefix mode the resultSet holds every trie whose subtree was already collected, so they're skipped.
+func (s *searcher[N, T]) collectSubtree(
+	step N,
+	distance int,
+	resultSet map[N]struct{},
+	collect func(t *T, distance int),
+	done func() bool,
+) {
+	stack := []N{step}
+
+	for len(stack) > 0 && !done() {
+		crtStep := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, alreadyCollected := resultSet[crtStep]; alreadyCollected {
+			continue
+		}
+		resultSet[crtStep] = struct{}{}
+
+		if value := crtStep.GetValue(); value != nil && (s.ownsRoot || crtStep != s.root) && (s.filter == nil || s.filter(value)) {
+			collect(value, distance)
+			s.stats.Results++
+		}
+
+		crtStep.Iterate(func(r rune, child N) {
+			if s.stepsFrom(crtStep, r) && (s.prune == nil || !s.prune(child)) {
+				stack = append(stack, child)
+			}
+		})
+	}
 }
@@ -0,0 +1,31 @@
+package fuzzy
+
+// Status tells why a search stopped.
+type Status int
+
+const (
+	// CollectorDone means the collector got all the results it needed.
+	CollectorDone Status = iota
+	// SearchSpaceExhausted means there is no more match within the distance.
+	SearchSpaceExhausted
+	// Canceled means the context got canceled before the search finished.
+	Canceled
+	// BudgetExhausted means the search reached one of the limits set in Options,
+	// the results collected so far are still ordered by distance but some matches may be missing.
+	BudgetExhausted
+)
+
+func (s Status) String() string {
+	switch s {
+	case CollectorDone:
+		return "collector done"
+	case SearchSpaceExhausted:
+		return "search space exhausted"
+	case Canceled:
+		return "canceled"
+	case BudgetExhausted:
+		return "budget exhausted"
+	}
+
+	return "unknown"
+}
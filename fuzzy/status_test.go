@@ -0,0 +1,39 @@
+package fuzzy
+
+import (
+	"context"
+	"errors"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"testing"
+)
+
+func TestSearchStatus(t *testing.T) {
+	testTrie := trie.New[string]()
+	words := []string{"cat", "tat", "dog"}
+
+	for i := range words {
+		testTrie.Insert(words[i], &words[i], trie.KeepLast[string])
+	}
+
+	for _, workers := range []int{1, 3} {
+		options := Options[string]{Workers: workers}
+
+		status, err := SearchWithOptions[string](context.Background(), testTrie, "cat", 1, NewListCollector[string](1), options)
+		if status != CollectorDone || err != nil {
+			t.Fatalf("%d workers: expected the collector to be done but got '%s'", workers, status)
+		}
+
+		status, err = SearchWithOptions[string](context.Background(), testTrie, "cat", 1, NewListCollector[string](10), options)
+		if status != SearchSpaceExhausted || err != nil {
+			t.Fatalf("%d workers: expected the search space to be exhausted but got '%s'", workers, status)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		status, err = SearchWithOptions[string](ctx, testTrie, "cat", 1, NewListCollector[string](10), options)
+		if status != Canceled || !errors.Is(err, context.Canceled) {
+			t.Fatalf("%d workers: expected the search to be canceled but got '%s'", workers, status)
+		}
+	}
+}
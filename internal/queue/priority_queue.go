@@ -0,0 +1,36 @@
+// Package queue is the priority queue of the fuzzy search, generic over the type of the nodes explored so that it
+// works with tries, DAWGs and frozen tries alike.
+package queue
+
+import "container/heap"
+
+type PriorityQueue[N any] struct {
+	itemArray *itemArray[N]
+}
+
+func New[N any]() *PriorityQueue[N] {
+	return &PriorityQueue[N]{
+		itemArray: &itemArray[N]{},
+	}
+}
+
+// Add an element to the priority queue. If item is nil, it's ignored.
+func (pq *PriorityQueue[N]) Add(item *Item[N]) {
+	if item != nil {
+		heap.Push(pq.itemArray, item)
+	}
+}
+
+// Pop and element from the priority queue. If the queue is empty, nil is returned.
+func (pq *PriorityQueue[N]) Pop() *Item[N] {
+	if pq.itemArray.Len() >= 1 {
+		return heap.Pop(pq.itemArray).(*Item[N])
+	}
+
+	return nil
+}
+
+// Len returns the number of items in the priority queue.
+func (pq *PriorityQueue[N]) Len() int {
+	return pq.itemArray.Len()
+}
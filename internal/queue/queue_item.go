@@ -0,0 +1,48 @@
+package queue
+
+// Item is a state of the search, N is the type of the nodes of the trie being explored.
+type Item[N any] struct {
+	// our position in the input string
+	Position int
+	// current step in the Trie we are exploring
+	Step N
+	// number of errors that can still be made
+	ErrorsLeft int
+}
+
+type itemArray[N any] []*Item[N]
+
+// we implement the heap.Interface methods
+
+func (ia itemArray[N]) Len() int {
+	return len(ia)
+}
+
+func (ia itemArray[N]) Less(i, j int) bool {
+	pqi := ia[i]
+	pqj := ia[j]
+
+	if pqi.ErrorsLeft != pqj.ErrorsLeft {
+		return pqi.ErrorsLeft > pqj.ErrorsLeft
+	}
+
+	return pqi.Position > pqj.Position
+}
+
+func (ia itemArray[N]) Swap(i, j int) {
+	ia[i], ia[j] = ia[j], ia[i]
+}
+
+func (ia *itemArray[N]) Push(x any) {
+	item := x.(*Item[N])
+	*ia = append(*ia, item)
+}
+
+func (ia *itemArray[N]) Pop() any {
+	old := *ia
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil // avoid memory leak
+	*ia = old[0 : n-1]
+	return item
+}
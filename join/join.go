@@ -0,0 +1,245 @@
+package join
+
+import (
+	"context"
+	"errors"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"sort"
+	"sync"
+)
+
+// Config describes how the rows of the two sides are matched.
+type Config[L any, R any] struct {
+	// LeftKey and RightKey return the keys compared between the two sides, they're required
+	LeftKey  func(left *L) string
+	RightKey func(right *R) string
+	// MaxDistance is the largest edit distance between the keys of a pair
+	MaxDistance int
+	// OneToOne matches every left row with at most one right row. The pairs are assigned greedily by increasing
+	// distance once all the right rows are read, so nothing is emitted before the right side is exhausted.
+	OneToOne bool
+	// Candidates is the number of closest left keys considered for every right row when OneToOne is set, if <= 0
+	// it's 5.
+	Candidates int
+	// Workers is the number of goroutines searching the left side, if <= 0 it's 1.
+	Workers int
+}
+
+// Pair is the result of the join for a right row. Left is nil if the right row didn't match any left row.
+type Pair[L any, R any] struct {
+	Left     *L
+	Right    *R
+	Distance int
+}
+
+// Report sums up the join.
+type Report[L any] struct {
+	Matched        int
+	UnmatchedRight int
+	// UnmatchedLeft holds the left rows that were not matched with any right row, in their original order
+	UnmatchedLeft []*L
+}
+
+// candidate is a left row within the distance of a right row.
+type candidate struct {
+	left     int
+	distance int
+}
+
+// rightResult holds the candidates of a right row, the closest first.
+type rightResult[R any] struct {
+	index      int
+	right      *R
+	candidates []candidate
+}
+
+// Join indexes the left rows in a trie, and searches the key of every right row read from the channel. emit is called
+// once for every right row with its closest left row, or with a nil left row if there is none within the distance.
+// The pairs are emitted in no particular order, but emit is only called by one goroutine at a time.
+// Join returns once the right channel is closed, or with ctx.Err() if the context is canceled.
+func Join[L any, R any](
+	ctx context.Context,
+	left []L,
+	right <-chan R,
+	config Config[L, R],
+	emit func(pair Pair[L, R]),
+) (*Report[L], error) {
+	if config.LeftKey == nil || config.RightKey == nil {
+		return nil, errors.New("config.LeftKey and config.RightKey are required")
+	}
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	maxCandidates := 1
+	if config.OneToOne {
+		maxCandidates = config.Candidates
+		if maxCandidates <= 0 {
+			maxCandidates = 5
+		}
+	}
+
+	// the left rows sharing a key are all kept
+	leftTrie := trie.New[[]int]()
+	for i := range left {
+		indexes := []int{i}
+		leftTrie.Insert(config.LeftKey(&left[i]), &indexes, trie.Append[int])
+	}
+
+	type indexedRow struct {
+		index int
+		row   *R
+	}
+
+	rows := make(chan indexedRow, 4*workers)
+	results := make(chan rightResult[R], 4*workers)
+	doneCh := ctx.Done()
+
+	// read the right rows and number them, so that the one to one assignment does not depend on the workers
+	go func() {
+		defer close(rows)
+
+		for index := 0; ; index++ {
+			select {
+			case <-doneCh:
+				return
+			case row, ok := <-right:
+				if !ok {
+					return
+				}
+
+				select {
+				case rows <- indexedRow{index: index, row: &row}:
+				case <-doneCh:
+					return
+				}
+			}
+		}
+	}()
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer waitGroup.Done()
+
+			for row := range rows {
+				collector := fuzzy.NewListCollector[[]int](maxCandidates)
+				fuzzy.Search[[]int](ctx, leftTrie, config.RightKey(row.row), config.MaxDistance, collector)
+
+				result := rightResult[R]{index: row.index, right: row.row}
+				for _, match := range collector.Results {
+					for _, leftIndex := range *match.Value {
+						if len(result.candidates) < maxCandidates {
+							result.candidates = append(result.candidates, candidate{left: leftIndex, distance: match.Distance})
+						}
+					}
+				}
+
+				select {
+				case results <- result:
+				case <-doneCh:
+				}
+			}
+		}()
+	}
+
+	go func() {
+		waitGroup.Wait()
+		close(results)
+	}()
+
+	report := &Report[L]{}
+	leftMatched := make([]bool, len(left))
+	var buffered []rightResult[R]
+
+	for result := range results {
+		if config.OneToOne {
+			buffered = append(buffered, result)
+			continue
+		}
+
+		if len(result.candidates) == 0 {
+			report.UnmatchedRight++
+			emit(Pair[L, R]{Right: result.right})
+			continue
+		}
+
+		best := result.candidates[0]
+		leftMatched[best.left] = true
+		report.Matched++
+		emit(Pair[L, R]{Left: &left[best.left], Right: result.right, Distance: best.distance})
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if config.OneToOne {
+		assignOneToOne(left, buffered, leftMatched, report, emit)
+	}
+
+	for i := range left {
+		if !leftMatched[i] {
+			report.UnmatchedLeft = append(report.UnmatchedLeft, &left[i])
+		}
+	}
+
+	return report, nil
+}
+
+// assignOneToOne pairs the right rows with their candidates by increasing distance, skipping the left rows that are
+// already taken, and emits the pairs in the order of the right rows.
+func assignOneToOne[L any, R any](
+	left []L,
+	results []rightResult[R],
+	leftMatched []bool,
+	report *Report[L],
+	emit func(pair Pair[L, R]),
+) {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].index < results[j].index
+	})
+
+	type edge struct {
+		right int
+		candidate
+	}
+
+	var edges []edge
+	for i, result := range results {
+		for _, c := range result.candidates {
+			edges = append(edges, edge{right: i, candidate: c})
+		}
+	}
+
+	// ties are broken by the order of the right rows, and then of the candidates
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].distance < edges[j].distance
+	})
+
+	assigned := make([]*candidate, len(results))
+	for i := range edges {
+		e := &edges[i]
+		if assigned[e.right] != nil || leftMatched[e.left] {
+			continue
+		}
+
+		assigned[e.right] = &e.candidate
+		leftMatched[e.left] = true
+	}
+
+	for i, result := range results {
+		if assigned[i] == nil {
+			report.UnmatchedRight++
+			emit(Pair[L, R]{Right: result.right})
+			continue
+		}
+
+		report.Matched++
+		emit(Pair[L, R]{Left: &left[assigned[i].left], Right: result.right, Distance: assigned[i].distance})
+	}
+}
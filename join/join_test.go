@@ -0,0 +1,151 @@
+package join
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+)
+
+type city struct {
+	Name    string
+	Country string
+}
+
+func cities(names ...string) <-chan city {
+	out := make(chan city, len(names))
+	for _, name := range names {
+		out <- city{Name: name}
+	}
+	close(out)
+
+	return out
+}
+
+func cityName(c *city) string {
+	return c.Name
+}
+
+func collectPairs(pairs *[]Pair[city, city]) func(pair Pair[city, city]) {
+	return func(pair Pair[city, city]) {
+		*pairs = append(*pairs, pair)
+	}
+}
+
+func pairNames(pairs []Pair[city, city]) []string {
+	var out []string
+	for _, pair := range pairs {
+		left := "-"
+		if pair.Left != nil {
+			left = pair.Left.Name
+		}
+		out = append(out, pair.Right.Name+">"+left)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func checkNames(t *testing.T, got []string, expected ...string) {
+	t.Helper()
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	left := []city{{Name: "Bern", Country: "CH"}, {Name: "Berlin", Country: "DE"}, {Name: "Paris", Country: "FR"}}
+
+	for _, workers := range []int{1, 4} {
+		var pairs []Pair[city, city]
+		report, err := Join[city, city](
+			context.Background(),
+			left,
+			cities("Bern", "Berln", "Pariss", "Tokyo", "Bren"),
+			Config[city, city]{LeftKey: cityName, RightKey: cityName, MaxDistance: 1, Workers: workers},
+			collectPairs(&pairs),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkNames(t, pairNames(pairs), "Berln>Berlin", "Bern>Bern", "Bren>Bern", "Pariss>Paris", "Tokyo>-")
+
+		if report.Matched != 4 || report.UnmatchedRight != 1 || len(report.UnmatchedLeft) != 0 {
+			t.Fatalf("unexpected report %+v", report)
+		}
+
+		for _, pair := range pairs {
+			if pair.Right.Name == "Bern" && pair.Distance != 0 || pair.Right.Name == "Bren" && pair.Distance != 1 {
+				t.Fatalf("unexpected distance %d for %s", pair.Distance, pair.Right.Name)
+			}
+		}
+	}
+}
+
+func TestJoinOneToOne(t *testing.T) {
+	left := []city{{Name: "Bern"}, {Name: "Berlin"}, {Name: "Paris"}, {Name: "Rome"}}
+
+	var pairs []Pair[city, city]
+	report, err := Join[city, city](
+		context.Background(),
+		left,
+		cities("Bren", "Bern", "Berln", "Parsi"),
+		Config[city, city]{LeftKey: cityName, RightKey: cityName, MaxDistance: 2, OneToOne: true, Workers: 2},
+		collectPairs(&pairs),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bern is taken by the exact match, and Bren has no other candidate within the distance
+	checkNames(t, pairNames(pairs), "Berln>Berlin", "Bern>Bern", "Bren>-", "Parsi>Paris")
+
+	// the pairs are emitted in the order of the right rows
+	if pairs[0].Right.Name != "Bren" || pairs[3].Right.Name != "Parsi" {
+		t.Fatal("the pairs should be in the order of the right rows")
+	}
+
+	if report.Matched != 3 || report.UnmatchedRight != 1 || len(report.UnmatchedLeft) != 1 || report.UnmatchedLeft[0].Name != "Rome" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestJoinDuplicateLeftKeys(t *testing.T) {
+	left := []city{{Name: "Paris", Country: "FR"}, {Name: "Paris", Country: "US"}}
+
+	var pairs []Pair[city, city]
+	report, err := Join[city, city](
+		context.Background(),
+		left,
+		cities("Paris", "Pari"),
+		Config[city, city]{LeftKey: cityName, RightKey: cityName, MaxDistance: 1, OneToOne: true},
+		collectPairs(&pairs),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Matched != 2 || pairs[0].Left.Country != "FR" || pairs[1].Left.Country != "US" || pairs[1].Distance != 1 {
+		t.Fatalf("both left rows should of been matched, got %+v", report)
+	}
+}
+
+func TestJoinCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the right channel is never closed
+	right := make(chan city)
+	_, err := Join[city, city](ctx, []city{{Name: "Bern"}}, right, Config[city, city]{LeftKey: cityName, RightKey: cityName}, func(Pair[city, city]) {})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
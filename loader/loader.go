@@ -0,0 +1,322 @@
+package loader
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type Format int
+
+const (
+	// TSV has one record per line with the columns separated by tabs, there is no quoting
+	TSV Format = iota
+	// CSV follows RFC 4180, the quoted columns can hold commas and new lines
+	CSV
+	// JSONL has one JSON object per line
+	JSONL
+)
+
+// ParseFormat returns the format matching a name or a file extension: tsv, csv, jsonl or json.
+func ParseFormat(name string) (Format, error) {
+	switch strings.TrimPrefix(strings.ToLower(name), ".") {
+	case "tsv", "tab", "txt":
+		return TSV, nil
+	case "csv":
+		return CSV, nil
+	case "jsonl", "json", "ndjson":
+		return JSONL, nil
+	}
+
+	return 0, fmt.Errorf("unknown dataset format '%s'", name)
+}
+
+// FormatFromFileName guesses the format of a dataset from its extension.
+func FormatFromFileName(fileName string) (Format, error) {
+	return ParseFormat(filepath.Ext(fileName))
+}
+
+// ErrTooManyErrors is returned by Load when more than Config.MaxErrors records could not be loaded.
+var ErrTooManyErrors = errors.New("too many errors")
+
+// Config describes how to build a trie from the records of the input.
+type Config[T any] struct {
+	Format Format
+	// Header tells if the first line of a TSV or CSV file holds the names of the columns.
+	Header bool
+	// KeyColumns are the indexes of the TSV or CSV columns making the key, they're joined with KeySeparator.
+	KeyColumns []int
+	// KeyFields are the string fields of the JSON Lines records making the key, they're joined with KeySeparator.
+	KeyFields    []string
+	KeySeparator string
+	// Keys extracts the keys of a record, a record can be indexed under several keys.
+	// If set, KeyColumns and KeyFields are ignored.
+	Keys func(record *Record) ([]string, error)
+	// Value builds the value of a record indexed under key, it's required.
+	// It's called once for every key of the record.
+	Value func(key string, record *Record) (*T, error)
+	// Combine merges the values inserted with the same key, see trie.Insert and trie.KeepFirst, trie.KeepLast or
+	// trie.Append. If nil, the first value is kept.
+	Combine func(t1 *T, t2 *T) *T
+	// MaxErrors stops the loading with ErrTooManyErrors once more than MaxErrors records could not be loaded.
+	// If MaxErrors <= 0 there's no limit.
+	MaxErrors int
+}
+
+// Report describes what happened while loading the input.
+type Report struct {
+	// Records is the number of records read
+	Records int
+	// Inserted is the number of keys inserted in the trie
+	Inserted int
+	// Errors holds the errors of the records that could not be loaded, they were skipped
+	Errors []*LineError
+}
+
+// Load reads all the records of the input and indexes them in a new trie. The records that can't be parsed or indexed
+// are skipped and reported in Report.Errors, the error returned is about the input itself or ErrTooManyErrors.
+func Load[T any](reader io.Reader, config Config[T]) (*trie.Trie[T], *Report, error) {
+	out := trie.New[T]()
+	report, err := LoadInto[T](out, reader, config)
+	if err != nil {
+		return nil, report, err
+	}
+
+	return out, report, nil
+}
+
+// LoadInto behaves like Load, but indexes the records in an existing trie.
+func LoadInto[T any](out *trie.Trie[T], reader io.Reader, config Config[T]) (*Report, error) {
+	if config.Value == nil {
+		return nil, errors.New("config.Value is required")
+	}
+
+	keys := config.Keys
+	if keys == nil {
+		keys = keysFromConfig(config)
+	}
+
+	combine := config.Combine
+	if combine == nil {
+		combine = trie.KeepFirst[T]
+	}
+
+	report := &Report{}
+
+	handleRecord := func(record *Record, recordErr error) error {
+		report.Records++
+
+		err := recordErr
+		if err == nil {
+			err = insertRecord[T](out, record, keys, config.Value, combine, report)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		var lineErr *LineError
+		if !errors.As(err, &lineErr) {
+			lineErr = &LineError{Line: record.Line, Column: -1, Err: err}
+		}
+		report.Errors = append(report.Errors, lineErr)
+
+		if config.MaxErrors > 0 && len(report.Errors) > config.MaxErrors {
+			return ErrTooManyErrors
+		}
+
+		return nil
+	}
+
+	var err error
+	switch config.Format {
+	case TSV:
+		err = readTabSeparatedValues(reader, config.Header, handleRecord)
+	case CSV:
+		err = readCommaSeparatedValues(reader, config.Header, handleRecord)
+	case JSONL:
+		err = readJSONLines(reader, handleRecord)
+	default:
+		err = errors.New("unknown dataset format")
+	}
+
+	return report, err
+}
+
+func insertRecord[T any](
+	out *trie.Trie[T],
+	record *Record,
+	keys func(record *Record) ([]string, error),
+	value func(key string, record *Record) (*T, error),
+	combine func(t1 *T, t2 *T) *T,
+	report *Report,
+) error {
+	recordKeys, err := keys(record)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range recordKeys {
+		// every key gets its own value, since combine may modify them
+		recordValue, err := value(key, record)
+		if err != nil {
+			return err
+		}
+
+		out.Insert(key, recordValue, combine)
+		report.Inserted++
+	}
+
+	return nil
+}
+
+func keysFromConfig[T any](config Config[T]) func(record *Record) ([]string, error) {
+	return func(record *Record) ([]string, error) {
+		var parts []string
+
+		if record.Fields != nil {
+			for _, field := range config.KeyFields {
+				part, err := record.Get(field)
+				if err != nil {
+					return nil, err
+				}
+				parts = append(parts, part)
+			}
+		} else {
+			for _, column := range config.KeyColumns {
+				part, err := record.Column(column)
+				if err != nil {
+					return nil, err
+				}
+				parts = append(parts, part)
+			}
+		}
+
+		key := strings.Join(parts, config.KeySeparator)
+		if len(key) == 0 {
+			return nil, &LineError{Line: record.Line, Column: -1, Err: errors.New("empty key")}
+		}
+
+		return []string{key}, nil
+	}
+}
+
+// readTabSeparatedValues reads a TSV file line by line. TSV has no quoting, the quotes are part of the columns, so that
+// a stray quote can't make a record swallow the following lines.
+func readTabSeparatedValues(reader io.Reader, withHeader bool, handleRecord func(record *Record, err error) error) error {
+	scanner := bufio.NewScanner(reader)
+	// some records can be quite long
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var header []string
+	var headerIndex map[string]int
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(text) == 0 {
+			continue
+		}
+
+		columns := strings.Split(text, "\t")
+
+		if withHeader && header == nil {
+			header, headerIndex = columns, indexHeader(columns)
+			continue
+		}
+
+		err := handleRecord(&Record{Line: line, Columns: columns, Header: header, headerIndex: headerIndex}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func readCommaSeparatedValues(reader io.Reader, withHeader bool, handleRecord func(record *Record, err error) error) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	var header []string
+	var headerIndex map[string]int
+
+	for {
+		columns, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// the reader can carry on with the next record
+			err = handleRecord(&Record{Line: parseErr.StartLine}, &LineError{
+				Line:   parseErr.Line,
+				Column: -1,
+				Err:    fmt.Errorf("byte %d: %w", parseErr.Column, parseErr.Err),
+			})
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		line, _ := csvReader.FieldPos(0)
+
+		if withHeader && header == nil {
+			header, headerIndex = columns, indexHeader(columns)
+			continue
+		}
+
+		err = handleRecord(&Record{Line: line, Columns: columns, Header: header, headerIndex: headerIndex}, nil)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// indexHeader maps the names of the columns to their index.
+func indexHeader(header []string) map[string]int {
+	headerIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		headerIndex[name] = i
+	}
+
+	return headerIndex
+}
+
+func readJSONLines(reader io.Reader, handleRecord func(record *Record, err error) error) error {
+	scanner := bufio.NewScanner(reader)
+	// some records can be quite long
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 {
+			continue
+		}
+
+		record := &Record{Line: line, Text: text}
+		var recordErr error
+
+		if err := json.Unmarshal([]byte(text), &record.Fields); err != nil || record.Fields == nil {
+			recordErr = &LineError{Line: line, Column: -1, Err: errors.New("not a JSON object")}
+		}
+
+		if err := handleRecord(record, recordErr); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,236 @@
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"strings"
+	"testing"
+)
+
+type testCity struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+func cityFromColumns(key string, record *Record) (*testCity, error) {
+	name, err := record.Column(1)
+	if err != nil {
+		return nil, err
+	}
+
+	latitude, err := record.Float(2)
+	if err != nil {
+		return nil, err
+	}
+
+	longitude, err := record.Float(3)
+	if err != nil {
+		return nil, err
+	}
+
+	return &testCity{Name: name, Latitude: latitude, Longitude: longitude}, nil
+}
+
+func step(t *trie.Trie[testCity], key string) *trie.Trie[testCity] {
+	for _, r := range key {
+		if t == nil {
+			return nil
+		}
+		t = t.Step(r)
+	}
+
+	return t
+}
+
+func TestLoadTSVReportsErrors(t *testing.T) {
+	input := "1\tBern\t46.9\t7.4\n" +
+		"2\tParis\tnot a float\t2.3\n" +
+		"3\tZurich\n" +
+		"4\tGeneva\t46.2\t6.1\n"
+
+	cityTrie, report, err := Load[testCity](strings.NewReader(input), Config[testCity]{
+		Format:     TSV,
+		KeyColumns: []int{1},
+		Value:      cityFromColumns,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Records != 4 || report.Inserted != 2 || len(report.Errors) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	if report.Errors[0].Line != 2 || report.Errors[0].Column != 2 || report.Errors[0].Error() != "line 2, column 2: 'not a float' is not a float" {
+		t.Fatalf("unexpected error '%s'", report.Errors[0].Error())
+	}
+
+	if report.Errors[1].Line != 3 || report.Errors[1].Column != 2 {
+		t.Fatalf("unexpected error '%s'", report.Errors[1].Error())
+	}
+
+	bern := step(cityTrie, "Bern")
+	if bern == nil || bern.Value == nil || bern.Value.Latitude != 46.9 {
+		t.Fatal("Bern should of been loaded")
+	}
+
+	if step(cityTrie, "Geneva") == nil || step(cityTrie, "Paris") != nil {
+		t.Fatal("only the valid records should of been loaded")
+	}
+}
+
+func TestLoadMaxErrors(t *testing.T) {
+	input := "1\tBern\tx\t7.4\n2\tParis\tx\t2.3\n3\tGeneva\t46.2\t6.1\n"
+
+	_, report, err := Load[testCity](strings.NewReader(input), Config[testCity]{
+		Format:     TSV,
+		KeyColumns: []int{1},
+		Value:      cityFromColumns,
+		MaxErrors:  1,
+	})
+
+	if !errors.Is(err, ErrTooManyErrors) || report.Records != 2 {
+		t.Fatalf("the loading should of stopped on the second error, got %v", err)
+	}
+}
+
+func TestLoadCSVWithHeaderAndSeveralKeyColumns(t *testing.T) {
+	input := "name,country\nBern,CH\n\"Paris, \"\"the city of light\"\"\",FR\nBern,US\n"
+
+	cityTrie, report, err := Load[[]string](strings.NewReader(input), Config[[]string]{
+		Format:       CSV,
+		Header:       true,
+		KeyColumns:   []int{0, 1},
+		KeySeparator: " ",
+		Value: func(key string, record *Record) (*[]string, error) {
+			country, err := record.Get("country")
+			if err != nil {
+				return nil, err
+			}
+
+			return &[]string{country}, nil
+		},
+		Combine: trie.Append[string],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Records != 3 || len(report.Errors) != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	var keys []string
+	cityTrie.WalkSorted(func(key string, _ *[]string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if strings.Join(keys, "|") != "Bern CH|Bern US|Paris, \"the city of light\" FR" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+}
+
+func TestLoadCSVParseError(t *testing.T) {
+	input := "Bern,CH\n\"Par\"is,FR\nGeneva,CH\n"
+
+	_, report, err := Load[string](strings.NewReader(input), Config[string]{
+		Format:     CSV,
+		KeyColumns: []int{0},
+		Value: func(key string, record *Record) (*string, error) {
+			return &record.Columns[1], nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Inserted != 2 || len(report.Errors) != 1 || report.Errors[0].Line != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestLoadJSONLines(t *testing.T) {
+	input := `{"name": "Bern", "aliases": ["Berne", "Berna"]}
+{"name": 12}
+not json
+
+{"name": "Paris", "aliases": []}
+`
+
+	cityTrie, report, err := Load[string](strings.NewReader(input), Config[string]{
+		Format: JSONL,
+		Keys: func(record *Record) ([]string, error) {
+			name, err := record.Get("name")
+			if err != nil {
+				return nil, err
+			}
+
+			var aliases []string
+			if err := json.Unmarshal(record.Fields["aliases"], &aliases); err != nil {
+				return nil, err
+			}
+
+			return append(aliases, name), nil
+		},
+		Value: func(key string, record *Record) (*string, error) {
+			name, err := record.Get("name")
+			return &name, err
+		},
+		Combine: trie.KeepLast[string],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Records != 4 || report.Inserted != 4 || len(report.Errors) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	if report.Errors[0].Line != 2 || report.Errors[1].Line != 3 {
+		t.Fatalf("unexpected errors %v and %v", report.Errors[0], report.Errors[1])
+	}
+
+	berna := cityTrie.Step('B').Step('e').Step('r').Step('n').Step('a')
+	if berna == nil || *berna.Value != "Bern" {
+		t.Fatal("the aliases should of been indexed")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := FormatFromFileName("data/cities.JSONL")
+	if err != nil || format != JSONL {
+		t.Fatal("unexpected format")
+	}
+
+	if _, err = ParseFormat("xml"); err == nil {
+		t.Fatal("xml is not supported")
+	}
+}
+
+func TestLoadTSVWithStrayQuotes(t *testing.T) {
+	input := "1\t\"Big\" Apple\tx\n2\tBern\ty\n3\t\"Zurich\tz\n4\tParis\tw\n"
+
+	cityTrie, report, err := Load[string](strings.NewReader(input), Config[string]{
+		Format:     TSV,
+		KeyColumns: []int{1},
+		Value: func(key string, record *Record) (*string, error) {
+			return &record.Columns[2], nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Records != 4 || report.Inserted != 4 || len(report.Errors) != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// the quotes are part of the keys
+	for key, value := range map[string]string{"\"Big\" Apple": "x", "Bern": "y", "\"Zurich": "z", "Paris": "w"} {
+		if got := cityTrie.Get(key); got == nil || *got != value {
+			t.Fatalf("%s should of been loaded with %s", key, value)
+		}
+	}
+}
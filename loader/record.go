@@ -0,0 +1,111 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Record is a single record of the input, a line of a TSV, CSV or JSON Lines file.
+type Record struct {
+	// Line is the line of the input the record starts on, starting at 1
+	Line int
+	// Columns holds the columns of a TSV or CSV record
+	Columns []string
+	// Fields holds the fields of a JSON Lines record
+	Fields map[string]json.RawMessage
+	// Text is the raw line of a JSON Lines record
+	Text string
+	// Header holds the names of the columns of a TSV or CSV file with a header
+	Header []string
+	// headerIndex maps the names of the columns to their index
+	headerIndex map[string]int
+}
+
+// Column returns the column at index i of a TSV or CSV record.
+func (r *Record) Column(i int) (string, error) {
+	if i < 0 || i >= len(r.Columns) {
+		return "", r.errorf(i, "no column %d", i)
+	}
+
+	return r.Columns[i], nil
+}
+
+// Get returns the column with that name for a TSV or CSV file with a header, or the string field with that name for
+// a JSON Lines file.
+func (r *Record) Get(name string) (string, error) {
+	if r.Fields != nil {
+		var value string
+		if err := json.Unmarshal(r.Fields[name], &value); err != nil {
+			return "", r.errorf(-1, "field '%s' is not a string", name)
+		}
+
+		return value, nil
+	}
+
+	i, ok := r.headerIndex[name]
+	if !ok {
+		return "", r.errorf(-1, "no column '%s'", name)
+	}
+
+	return r.Column(i)
+}
+
+// Float parses the column at index i of a TSV or CSV record as a float.
+func (r *Record) Float(i int) (float64, error) {
+	column, err := r.Column(i)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseFloat(column, 64)
+	if err != nil {
+		return 0, r.errorf(i, "'%s' is not a float", column)
+	}
+
+	return value, nil
+}
+
+// Int parses the column at index i of a TSV or CSV record as an integer.
+func (r *Record) Int(i int) (int, error) {
+	column, err := r.Column(i)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(column)
+	if err != nil {
+		return 0, r.errorf(i, "'%s' is not an integer", column)
+	}
+
+	return value, nil
+}
+
+func (r *Record) errorf(column int, format string, a ...any) *LineError {
+	return &LineError{
+		Line:   r.Line,
+		Column: column,
+		Err:    fmt.Errorf(format, a...),
+	}
+}
+
+// LineError is an error on a record of the input.
+type LineError struct {
+	// Line of the input, starting at 1
+	Line int
+	// Column is the index of the column that caused the error, or -1 if it's not about a column
+	Column int
+	Err    error
+}
+
+func (e *LineError) Error() string {
+	if e.Column >= 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Err.Error())
+	}
+
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err.Error())
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
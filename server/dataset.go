@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"github.com/marcadamsge/gofuzzy/loader"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"io"
+	"strconv"
+)
+
+// DatasetConfig describes how to index a dataset.
+type DatasetConfig struct {
+	Format loader.Format
+	// Header tells if the first line of a TSV or CSV file holds the names of the columns.
+	// The records are then returned as JSON objects, otherwise they're returned as JSON arrays.
+	Header bool
+	// KeyColumn is the index of the column to index in a TSV or CSV file.
+	KeyColumn int
+	// KeyField is the field to index in a JSON Lines file.
+	KeyField string
+}
+
+// Entry holds all the records indexed with the same key.
+type Entry struct {
+	Key     string            `json:"key"`
+	Records []json.RawMessage `json:"records"`
+}
+
+func combineEntries(e1 *Entry, e2 *Entry) *Entry {
+	if e1 != nil && e2 != nil {
+		e1.Records = append(e1.Records, e2.Records...)
+		return e1
+	}
+
+	if e1 != nil {
+		return e1
+	}
+
+	return e2
+}
+
+// LoadDataset indexes all the records of the dataset by key. The records that can't be loaded are skipped and listed
+// in the report.
+func LoadDataset(reader io.Reader, config DatasetConfig) (*trie.Trie[Entry], *loader.Report, error) {
+	return loader.Load[Entry](reader, loader.Config[Entry]{
+		Format:     config.Format,
+		Header:     config.Header,
+		KeyColumns: []int{config.KeyColumn},
+		KeyFields:  []string{config.KeyField},
+		Value:      entryFromRecord,
+		Combine:    combineEntries,
+	})
+}
+
+func entryFromRecord(key string, record *loader.Record) (*Entry, error) {
+	var err error
+	var recordJSON []byte
+
+	if record.Fields != nil {
+		recordJSON = []byte(record.Text)
+	} else if record.Header != nil {
+		object := make(map[string]string, len(record.Columns))
+		for i, column := range record.Columns {
+			name := strconv.Itoa(i)
+			if i < len(record.Header) {
+				name = record.Header[i]
+			}
+			object[name] = column
+		}
+		recordJSON, err = json.Marshal(object)
+	} else {
+		recordJSON, err = json.Marshal(record.Columns)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		Key:     key,
+		Records: []json.RawMessage{recordJSON},
+	}, nil
+}
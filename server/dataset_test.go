@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/loader"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"strings"
+	"testing"
+)
+
+func lookup(t *testing.T, dataset *trie.Trie[Entry], key string) *Entry {
+	collector := fuzzy.NewListCollector[Entry](1)
+	fuzzy.Search[Entry](context.Background(), dataset, key, 0, collector)
+
+	if len(collector.Results) != 1 {
+		t.Fatalf("key '%s' was not indexed", key)
+	}
+
+	return collector.Results[0].Value
+}
+
+func TestLoadTSV(t *testing.T) {
+	dataset, report, err := LoadDataset(strings.NewReader("1\tbern\tCH\n2\tparis\tFR\n3\tbern\tUS\n"), DatasetConfig{
+		Format:    loader.TSV,
+		KeyColumn: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Inserted != 3 {
+		t.Fatalf("expected 3 records but got %d", report.Inserted)
+	}
+
+	bern := lookup(t, dataset, "bern")
+	if bern.Key != "bern" || len(bern.Records) != 2 || string(bern.Records[0]) != `["1","bern","CH"]` {
+		t.Fatalf("unexpected entry %+v", bern)
+	}
+}
+
+func TestLoadCSVWithHeader(t *testing.T) {
+	dataset, report, err := LoadDataset(strings.NewReader("id,name\n1,\"bern, CH\"\n2,paris\n"), DatasetConfig{
+		Format:    loader.CSV,
+		Header:    true,
+		KeyColumn: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Inserted != 2 {
+		t.Fatalf("expected 2 records but got %d", report.Inserted)
+	}
+
+	bern := lookup(t, dataset, "bern, CH")
+	if string(bern.Records[0]) != `{"id":"1","name":"bern, CH"}` {
+		t.Fatalf("unexpected record %s", string(bern.Records[0]))
+	}
+
+	_, report, err = LoadDataset(strings.NewReader("id,name\n1,bern\n2\n"), DatasetConfig{
+		Format:    loader.CSV,
+		Header:    true,
+		KeyColumn: 1,
+	})
+	if err != nil || len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Fatalf("expected an error on line 3 but got %v", report.Errors)
+	}
+}
+
+func TestLoadJSONLines(t *testing.T) {
+	input := `{"name": "bern", "population": 133883}
+
+{"name": "paris", "population": 2138551}
+`
+	dataset, report, err := LoadDataset(strings.NewReader(input), DatasetConfig{
+		Format:   loader.JSONL,
+		KeyField: "name",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Inserted != 2 {
+		t.Fatalf("expected 2 records but got %d", report.Inserted)
+	}
+
+	if lookup(t, dataset, "bern").Key != "bern" {
+		t.Fatal("the key of the entry should be set")
+	}
+
+	paris := lookup(t, dataset, "paris")
+	if string(paris.Records[0]) != `{"name": "paris", "population": 2138551}` {
+		t.Fatalf("unexpected record %s", string(paris.Records[0]))
+	}
+
+	_, report, err = LoadDataset(strings.NewReader(`{"name": 1}`), DatasetConfig{Format: loader.JSONL, KeyField: "name"})
+	if err != nil || len(report.Errors) != 1 || report.Errors[0].Line != 1 {
+		t.Fatalf("expected an error on line 1 but got %v", report.Errors)
+	}
+}
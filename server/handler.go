@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Config sets the limits of the queries the handler accepts.
+type Config struct {
+	// Timeout of a single query, it's ignored if <= 0
+	Timeout time.Duration
+	// MaxDistance is the largest distance a query can ask for
+	MaxDistance int
+	// DefaultLimit is the number of results returned when the query does not set a limit
+	DefaultLimit int
+	// MaxLimit is the largest limit a query can ask for
+	MaxLimit int
+	// MaxNodesVisited caps the work done by a single query, it's ignored if <= 0
+	MaxNodesVisited int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:         time.Second,
+		MaxDistance:     3,
+		DefaultLimit:    10,
+		MaxLimit:        100,
+		MaxNodesVisited: 0,
+	}
+}
+
+// Handler serves the dataset with the following endpoints:
+//   - /search?q=bern&distance=1&limit=10&prefix=false returns the keys within the distance of q
+//   - /complete?q=ber&distance=0&limit=10 returns the keys starting with a prefix within the distance of q
+//   - /validate?q=bern&distance=1&limit=10 tells if q is in the dataset, and returns suggestions if it's not
+//
+// All the endpoints accept a rank parameter sorting the results with the same distance, see fuzzy.ParseRanking.
+type Handler struct {
+	dataset *trie.Trie[Entry]
+	config  Config
+	mux     *http.ServeMux
+}
+
+func NewHandler(dataset *trie.Trie[Entry], config Config) *Handler {
+	h := &Handler{
+		dataset: dataset,
+		config:  config,
+		mux:     http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("/search", h.handleSearch)
+	h.mux.HandleFunc("/complete", h.handleComplete)
+	h.mux.HandleFunc("/validate", h.handleValidate)
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+type SearchResult struct {
+	Key      string            `json:"key"`
+	Distance int               `json:"distance"`
+	Records  []json.RawMessage `json:"records"`
+}
+
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+	// Status is why the search stopped, see fuzzy.Status
+	Status string `json:"status"`
+}
+
+type ValidateResponse struct {
+	Query string `json:"query"`
+	Valid bool   `json:"valid"`
+	// Records holds the records of the query if it's valid
+	Records []json.RawMessage `json:"records,omitempty"`
+	// Suggestions holds the closest keys if the query is not valid
+	Suggestions []SearchResult `json:"suggestions"`
+	Status      string         `json:"status"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// query holds the parameters of a request.
+type query struct {
+	str      string
+	distance int
+	limit    int
+	prefix   bool
+	ranking  fuzzy.Ranking
+}
+
+func (h *Handler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q, ok := h.parseQuery(w, r, 1)
+	if !ok {
+		return
+	}
+
+	h.respondSearch(w, r, q)
+}
+
+func (h *Handler) handleComplete(w http.ResponseWriter, r *http.Request) {
+	q, ok := h.parseQuery(w, r, 0)
+	if !ok {
+		return
+	}
+
+	q.prefix = true
+	h.respondSearch(w, r, q)
+}
+
+func (h *Handler) handleValidate(w http.ResponseWriter, r *http.Request) {
+	q, ok := h.parseQuery(w, r, 1)
+	if !ok {
+		return
+	}
+
+	if q.prefix {
+		// a key starting with q would make q look valid
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "prefix is not supported by /validate"})
+		return
+	}
+
+	// one more result in case the first one is the exact match
+	q.limit++
+	results, status, ok := h.search(w, r, q)
+	if !ok {
+		return
+	}
+
+	response := ValidateResponse{
+		Query:       q.str,
+		Suggestions: make([]SearchResult, 0, len(results)),
+		Status:      status.String(),
+	}
+
+	for _, result := range results {
+		if result.Distance == 0 {
+			// without prefix only q itself is at distance 0, the records are merged in case that ever changes
+			response.Valid = true
+			response.Records = append(response.Records, result.Records...)
+		} else {
+			response.Suggestions = append(response.Suggestions, result)
+		}
+	}
+
+	if len(response.Suggestions) == q.limit {
+		response.Suggestions = response.Suggestions[:q.limit-1]
+	}
+
+	if response.Valid {
+		// no need for suggestions
+		response.Suggestions = response.Suggestions[:0]
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) respondSearch(w http.ResponseWriter, r *http.Request, q query) {
+	results, status, ok := h.search(w, r, q)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, SearchResponse{
+		Query:   q.str,
+		Results: results,
+		Status:  status.String(),
+	})
+}
+
+// search runs the query, if it fails the error is written to w and ok is false.
+func (h *Handler) search(w http.ResponseWriter, r *http.Request, q query) (results []SearchResult, status fuzzy.Status, ok bool) {
+	ctx := r.Context()
+	if h.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.config.Timeout)
+		defer cancel()
+	}
+
+	collector := fuzzy.NewRankingCollector[Entry](q.limit, q.str, entryKey, q.ranking)
+	status, err := fuzzy.SearchWithOptions[Entry](ctx, h.dataset, q.str, q.distance, collector, fuzzy.Options[Entry]{
+		MaxNodesVisited: h.config.MaxNodesVisited,
+		Prefix:          q.prefix,
+	})
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		writeJSON(w, http.StatusGatewayTimeout, errorResponse{Error: "the search timed out"})
+		return nil, status, false
+	}
+
+	if err != nil {
+		// the client is gone, nobody is listening anymore
+		return nil, status, false
+	}
+
+	results = make([]SearchResult, 0, len(collector.Results))
+	for _, result := range collector.Results {
+		results = append(results, SearchResult{
+			Key:      result.Value.Key,
+			Distance: result.Distance,
+			Records:  result.Value.Records,
+		})
+	}
+
+	return results, status, true
+}
+
+// parseQuery reads the parameters of the request, if they're invalid the error is written to w and ok is false.
+func (h *Handler) parseQuery(w http.ResponseWriter, r *http.Request, defaultDistance int) (q query, ok bool) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "only GET is allowed"})
+		return q, false
+	}
+
+	values := r.URL.Query()
+	if !values.Has("q") {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "the q parameter is required"})
+		return q, false
+	}
+
+	q.str = values.Get("q")
+	var err error
+
+	q.distance, err = intParameter(values.Get("distance"), defaultDistance, 0, h.config.MaxDistance)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "distance " + err.Error()})
+		return q, false
+	}
+
+	q.limit, err = intParameter(values.Get("limit"), h.config.DefaultLimit, 1, h.config.MaxLimit)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "limit " + err.Error()})
+		return q, false
+	}
+
+	if prefix := values.Get("prefix"); prefix != "" {
+		q.prefix, err = strconv.ParseBool(prefix)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "prefix should be true or false"})
+			return q, false
+		}
+	}
+
+	if rank := values.Get("rank"); rank != "" {
+		q.ranking, err = fuzzy.ParseRanking(rank)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "rank should be distance, jaro-winkler, common-prefix or bigram-dice"})
+			return q, false
+		}
+	}
+
+	return q, true
+}
+
+func entryKey(entry *Entry) string {
+	return entry.Key
+}
+
+func intParameter(value string, defaultValue int, min int, max int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	out, err := strconv.Atoi(value)
+	if err != nil || out < min || out > max {
+		return 0, fmt.Errorf("should be an integer between %d and %d", min, max)
+	}
+
+	return out, nil
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/marcadamsge/gofuzzy/loader"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestHandler(t *testing.T) *Handler {
+	input := "bern\tCH\nberne\tUS\nberlin\tDE\nparis\tFR\n"
+	dataset, _, err := LoadDataset(strings.NewReader(input), DatasetConfig{Format: loader.TSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewHandler(dataset, DefaultConfig())
+}
+
+func get(t *testing.T, handler http.Handler, url string, expectedStatus int, response any) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+
+	if recorder.Code != expectedStatus {
+		t.Fatalf("%s: expected status %d but got %d: %s", url, expectedStatus, recorder.Code, recorder.Body.String())
+	}
+
+	if recorder.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("%s: the response should be JSON", url)
+	}
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchEndpoint(t *testing.T) {
+	handler := newTestHandler(t)
+
+	var response SearchResponse
+	get(t, handler, "/search?q=bern&distance=1&limit=5", http.StatusOK, &response)
+
+	if response.Query != "bern" || len(response.Results) != 2 || response.Status != "search space exhausted" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+
+	if response.Results[0].Key != "bern" || response.Results[0].Distance != 0 || string(response.Results[0].Records[0]) != `["bern","CH"]` {
+		t.Fatalf("unexpected first result %+v", response.Results[0])
+	}
+
+	if response.Results[1].Key != "berne" || response.Results[1].Distance != 1 {
+		t.Fatalf("unexpected second result %+v", response.Results[1])
+	}
+
+	get(t, handler, "/search?q=bern&distance=1&limit=1", http.StatusOK, &response)
+	if len(response.Results) != 1 || response.Status != "collector done" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+
+	get(t, handler, "/search?q=ber&prefix=true&limit=5", http.StatusOK, &response)
+	if len(response.Results) != 3 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+}
+
+func TestSearchEndpointRanking(t *testing.T) {
+	handler := newTestHandler(t)
+
+	var response SearchResponse
+	// bern and berne are both 1 edit away, but bern shares more bigrams with bernn
+	get(t, handler, "/search?q=bernn&distance=1&limit=1&rank=bigram-dice", http.StatusOK, &response)
+	if len(response.Results) != 1 || response.Results[0].Key != "bern" || response.Results[0].Distance != 1 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+}
+
+func TestCompleteEndpoint(t *testing.T) {
+	handler := newTestHandler(t)
+
+	var response SearchResponse
+	get(t, handler, "/complete?q=berl", http.StatusOK, &response)
+
+	if len(response.Results) != 1 || response.Results[0].Key != "berlin" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+
+	get(t, handler, "/complete?q=pra&distance=1", http.StatusOK, &response)
+	if len(response.Results) != 1 || response.Results[0].Key != "paris" || response.Results[0].Distance != 1 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	handler := newTestHandler(t)
+
+	var response ValidateResponse
+	get(t, handler, "/validate?q=paris", http.StatusOK, &response)
+
+	if !response.Valid || string(response.Records[0]) != `["paris","FR"]` || len(response.Suggestions) != 0 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+
+	response = ValidateResponse{}
+	get(t, handler, "/validate?q=bernn&limit=1", http.StatusOK, &response)
+	if response.Valid || len(response.Records) != 0 || len(response.Suggestions) != 1 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+
+	response = ValidateResponse{}
+	get(t, handler, "/validate?q=bernn&limit=5", http.StatusOK, &response)
+	if response.Valid || len(response.Suggestions) != 2 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	handler := newTestHandler(t)
+
+	var response errorResponse
+	get(t, handler, "/search", http.StatusBadRequest, &response)
+	get(t, handler, "/search?q=bern&distance=10", http.StatusBadRequest, &response)
+	get(t, handler, "/search?q=bern&distance=a", http.StatusBadRequest, &response)
+	get(t, handler, "/search?q=bern&limit=0", http.StatusBadRequest, &response)
+	get(t, handler, "/search?q=bern&prefix=maybe", http.StatusBadRequest, &response)
+	get(t, handler, "/search?q=bern&rank=soundex", http.StatusBadRequest, &response)
+	get(t, handler, "/validate?q=ber&prefix=true", http.StatusBadRequest, &response)
+
+	if response.Error == "" {
+		t.Fatal("the error should be explained")
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/search?q=bern", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("unexpected status %d", recorder.Code)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	handler := newTestHandler(t)
+
+	// the deadline is already passed when the search starts
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/search?q=bern", nil).WithContext(ctx))
+
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Fatalf("unexpected status %d", recorder.Code)
+	}
+}
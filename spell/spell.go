@@ -0,0 +1,272 @@
+package spell
+
+import (
+	"bufio"
+	"context"
+	"github.com/marcadamsge/gofuzzy/fuzzy"
+	"github.com/marcadamsge/gofuzzy/trie"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Term is the value of every word of the dictionary.
+type Term struct {
+	Word string
+	// Count is the number of times the word was seen, it's the weight of the word in the language model
+	Count int
+}
+
+// CombineTerms adds up the counts of a word inserted several times, it can be given to trie.Insert.
+func CombineTerms(t1 *Term, t2 *Term) *Term {
+	if t1 != nil && t2 != nil {
+		t1.Count += t2.Count
+		return t1
+	}
+
+	if t1 != nil {
+		return t1
+	}
+
+	return t2
+}
+
+// CountWords builds a dictionary with the words of the reader, counting how many times every word appears.
+// The words are lower cased.
+func CountWords(reader io.Reader) (*trie.Trie[Term], error) {
+	words := trie.New[Term]()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		for _, token := range tokenize(scanner.Text()) {
+			word := strings.ToLower(token.text)
+			words.Insert(word, &Term{Word: word, Count: 1}, CombineTerms)
+		}
+	}
+
+	if scanner.Err() != nil {
+		return nil, scanner.Err()
+	}
+
+	return words, nil
+}
+
+// Config tunes the corrector.
+type Config struct {
+	// MaxDistance returns the number of edits allowed to correct a word of length runes.
+	// If nil, DefaultMaxDistance is used.
+	MaxDistance func(length int) int
+	// ErrorProbability is the probability of a single edit when typing a word, the probability of typing a word at
+	// distance d of the intended one is ErrorProbability^d. If <= 0, 0.01 is used.
+	ErrorProbability float64
+	// MaxCandidates is the number of candidates considered for a word, the closest ones are kept. If <= 0, 50 is used.
+	MaxCandidates int
+}
+
+// DefaultMaxDistance does not correct the words up to 2 runes, allows 1 edit up to 6 runes and 2 edits otherwise.
+func DefaultMaxDistance(length int) int {
+	if length <= 2 {
+		return 0
+	}
+
+	if length <= 6 {
+		return 1
+	}
+
+	return 2
+}
+
+// Corrector picks the most likely correction of the words with a noisy channel model: a candidate c for a typed
+// word w is scored with P(c) * P(w|c), where P(c) is the frequency of c in the dictionary and P(w|c) depends on the
+// edit distance between w and c.
+type Corrector struct {
+	words  *trie.Trie[Term]
+	total  int
+	config Config
+}
+
+func NewCorrector(words *trie.Trie[Term], config Config) *Corrector {
+	if config.MaxDistance == nil {
+		config.MaxDistance = DefaultMaxDistance
+	}
+
+	if config.ErrorProbability <= 0 {
+		config.ErrorProbability = 0.01
+	}
+
+	if config.MaxCandidates <= 0 {
+		config.MaxCandidates = 50
+	}
+
+	total := 0
+	words.Walk(func(_ string, term *Term) bool {
+		total += term.Count
+		return true
+	})
+
+	return &Corrector{
+		words:  words,
+		total:  total,
+		config: config,
+	}
+}
+
+// Candidate is a possible correction of a word.
+type Candidate struct {
+	Word     string
+	Distance int
+	// Probability is the probability that the candidate is the intended word, among all the candidates
+	Probability float64
+}
+
+// Candidates returns the possible corrections of the word, the most likely first.
+func (c *Corrector) Candidates(ctx context.Context, word string) ([]Candidate, error) {
+	lowerWord := strings.ToLower(word)
+	distance := c.config.MaxDistance(utf8.RuneCountInString(lowerWord))
+
+	collector := fuzzy.NewListCollector[Term](c.config.MaxCandidates)
+	_, err := fuzzy.SearchWithOptions[Term](ctx, c.words, lowerWord, distance, collector, fuzzy.Options[Term]{})
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]Candidate, 0, len(collector.Results))
+	sum := 0.0
+	for _, result := range collector.Results {
+		score := float64(result.Value.Count) / float64(c.total) * math.Pow(c.config.ErrorProbability, float64(result.Distance))
+		candidates = append(candidates, Candidate{
+			Word:        result.Value.Word,
+			Distance:    result.Distance,
+			Probability: score,
+		})
+		sum += score
+	}
+
+	for i := range candidates {
+		candidates[i].Probability /= sum
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Probability > candidates[j].Probability
+	})
+
+	return candidates, nil
+}
+
+// WordCorrection is the correction of a word of a text.
+type WordCorrection struct {
+	// Start and End are the byte offsets of the word in the text
+	Start    int
+	End      int
+	Original string
+	// Correction is the most likely word, with the case of the original word. It's the original word if there's no
+	// candidate.
+	Correction string
+	// Confidence is the probability that Correction is the intended word, it's 0 if there's no candidate
+	Confidence float64
+}
+
+// Changed tells if the word was corrected.
+func (w *WordCorrection) Changed() bool {
+	return w.Correction != w.Original
+}
+
+// Correction is the correction of a whole text.
+type Correction struct {
+	// Text is the corrected text
+	Text string
+	// Words holds the correction of every word of the text, in order
+	Words []WordCorrection
+}
+
+// Correct corrects every word of the text, everything between the words is kept as is.
+func (c *Corrector) Correct(ctx context.Context, text string) (*Correction, error) {
+	out := &Correction{}
+	var corrected strings.Builder
+	last := 0
+
+	for _, token := range tokenize(text) {
+		candidates, err := c.Candidates(ctx, token.text)
+		if err != nil {
+			return nil, err
+		}
+
+		correction := WordCorrection{
+			Start:      token.start,
+			End:        token.end,
+			Original:   token.text,
+			Correction: token.text,
+		}
+
+		if len(candidates) > 0 {
+			correction.Correction = matchCase(candidates[0].Word, token.text)
+			correction.Confidence = candidates[0].Probability
+		}
+
+		out.Words = append(out.Words, correction)
+		corrected.WriteString(text[last:token.start])
+		corrected.WriteString(correction.Correction)
+		last = token.end
+	}
+
+	corrected.WriteString(text[last:])
+	out.Text = corrected.String()
+
+	return out, nil
+}
+
+type token struct {
+	text  string
+	start int
+	end   int
+}
+
+// tokenize returns the words of the text, a word being a sequence of letters, possibly with apostrophes inside.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || (r == '\'' && start >= 0)
+		if isWordRune && start < 0 {
+			start = i
+		} else if !isWordRune && start >= 0 {
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+
+	return tokens
+}
+
+func newToken(text string, start int, end int) token {
+	// a trailing apostrophe is a quote, not part of the word
+	for end > start && text[end-1] == '\'' {
+		end--
+	}
+
+	return token{text: text[start:end], start: start, end: end}
+}
+
+// matchCase returns the word with the case of original: upper case, capitalized or as is.
+func matchCase(word string, original string) string {
+	if strings.ToUpper(original) == original && strings.ToLower(original) != original && utf8.RuneCountInString(original) > 1 {
+		return strings.ToUpper(word)
+	}
+
+	first, _ := utf8.DecodeRuneInString(original)
+	if unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(word)
+		return string(unicode.ToUpper(r)) + word[size:]
+	}
+
+	return word
+}
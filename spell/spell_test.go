@@ -0,0 +1,102 @@
+package spell
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const corpus = `The quick brown fox jumps over the lazy dog. The dog sleeps, the fox runs.
+Spelling is hard, but spelling correction is easy when the dictionary knows the words.
+The word there is more common than three, there there there.`
+
+func newTestCorrector(t *testing.T) *Corrector {
+	words, err := CountWords(strings.NewReader(corpus))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewCorrector(words, Config{})
+}
+
+func TestCountWords(t *testing.T) {
+	words, err := CountWords(strings.NewReader(corpus))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if term := words.Get("the"); term == nil || term.Count != 7 || term.Word != "the" {
+		t.Fatalf("unexpected term %v", term)
+	}
+
+	if words.Get("The") != nil || words.Get("dog.") != nil {
+		t.Fatal("the words should be lower cased and without punctuation")
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	corrector := newTestCorrector(t)
+
+	candidates, err := corrector.Candidates(context.Background(), "thre")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the, there and three are all one edit away, they're ranked by frequency
+	if len(candidates) != 3 || candidates[0].Word != "the" || candidates[1].Word != "there" || candidates[2].Word != "three" {
+		t.Fatalf("unexpected candidates %v", candidates)
+	}
+
+	sum := 0.0
+	for _, candidate := range candidates {
+		sum += candidate.Probability
+	}
+	if sum < 0.999 || sum > 1.001 {
+		t.Fatalf("the probabilities should add up to 1, got %f", sum)
+	}
+
+	// a known word is much more likely than a more frequent word one edit away
+	candidates, err = corrector.Candidates(context.Background(), "three")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if candidates[0].Word != "three" || candidates[0].Distance != 0 || candidates[0].Probability < 0.9 {
+		t.Fatalf("unexpected candidates %v", candidates)
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	corrector := newTestCorrector(t)
+
+	correction, err := corrector.Correct(context.Background(), "Teh quikc brown fox, SPELING is hrad!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if correction.Text != "The quick brown fox, SPELLING is hard!" {
+		t.Fatalf("unexpected correction '%s'", correction.Text)
+	}
+
+	if len(correction.Words) != 7 {
+		t.Fatalf("unexpected number of words %d", len(correction.Words))
+	}
+
+	first := correction.Words[0]
+	if first.Original != "Teh" || first.Correction != "The" || !first.Changed() || first.Start != 0 || first.End != 3 {
+		t.Fatalf("unexpected first word %+v", first)
+	}
+
+	if correction.Words[2].Changed() || correction.Words[2].Confidence < 0.9 {
+		t.Fatalf("brown should not of been changed %+v", correction.Words[2])
+	}
+
+	correction, err = corrector.Correct(context.Background(), "xylophone")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if correction.Text != "xylophone" || correction.Words[0].Confidence != 0 {
+		t.Fatal("an unknown word without candidates should be kept")
+	}
+}
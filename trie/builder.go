@@ -0,0 +1,202 @@
+package trie
+
+import (
+	"errors"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrNotSorted is returned when the keys given to a Builder are not sorted.
+var ErrNotSorted = errors.New("the keys are not sorted")
+
+// Builder constructs a trie from keys added in sorted order. Since the keys are sorted, the children of a trie are
+// all known once a key that does not start with it is added, so every trie is created bottom-up with a map of the
+// exact size of its children, and the tries without children don't get a map at all.
+// This is a lot faster, and uses less memory, than inserting the keys one by one.
+type Builder[T any] struct {
+	combineValues func(t1 *T, t2 *T) *T
+	// path holds the tries of the last key added, from the root to the trie of the key
+	path    []builderNode[T]
+	lastKey string
+}
+
+// builderNode is a trie of the path of the last key added, its children are not known yet.
+type builderNode[T any] struct {
+	r        rune
+	value    *T
+	children []builderChild[T]
+}
+
+type builderChild[T any] struct {
+	r    rune
+	trie *Trie[T]
+}
+
+// KeyValue is a key and its value, as given to BuildSorted and BuildParallel.
+type KeyValue[T any] struct {
+	Key   string
+	Value *T
+}
+
+// NewBuilder returns a builder merging the values added with the same key with combineValues, like Insert.
+func NewBuilder[T any](combineValues func(t1 *T, t2 *T) *T) *Builder[T] {
+	return &Builder[T]{
+		combineValues: combineValues,
+		path:          []builderNode[T]{{}},
+	}
+}
+
+// Add the key to the trie. The keys must be added in increasing order (the order of the < operator on strings),
+// otherwise ErrNotSorted is returned and the key is not added.
+func (builder *Builder[T]) Add(key string, value *T) error {
+	if key < builder.lastKey {
+		return ErrNotSorted
+	}
+
+	// the tries of the prefix shared with the last key are still open, the ones past it are complete
+	suffix := key
+	lastSuffix := builder.lastKey
+	shared := 0
+	for len(suffix) > 0 && len(lastSuffix) > 0 {
+		r, size := utf8.DecodeRuneInString(suffix)
+		lastR, lastSize := utf8.DecodeRuneInString(lastSuffix)
+		if r != lastR {
+			break
+		}
+
+		suffix = suffix[size:]
+		lastSuffix = lastSuffix[lastSize:]
+		shared++
+	}
+
+	builder.closePath(shared + 1)
+	for _, r := range suffix {
+		builder.path = append(builder.path, builderNode[T]{r: r})
+	}
+
+	last := &builder.path[len(builder.path)-1]
+	last.value = builder.combineValues(last.value, value)
+	builder.lastKey = key
+
+	return nil
+}
+
+// closePath creates the tries of the path past length, and adds them to the children of their parent.
+func (builder *Builder[T]) closePath(length int) {
+	for len(builder.path) > length {
+		node := builder.path[len(builder.path)-1]
+		builder.path = builder.path[:len(builder.path)-1]
+
+		parent := &builder.path[len(builder.path)-1]
+		parent.children = append(parent.children, builderChild[T]{r: node.r, trie: node.build()})
+	}
+}
+
+func (node *builderNode[T]) build() *Trie[T] {
+	out := &Trie[T]{Value: node.value}
+	if len(node.children) > 0 {
+		out.children = make(map[rune]*Trie[T], len(node.children))
+		for _, child := range node.children {
+			out.children[child.r] = child.trie
+		}
+	}
+
+	return out
+}
+
+// Build returns the trie of all the keys added. The builder is reset and can be used to build another trie.
+func (builder *Builder[T]) Build() *Trie[T] {
+	builder.closePath(1)
+	out := builder.path[0].build()
+
+	builder.path[0] = builderNode[T]{}
+	builder.lastKey = ""
+
+	return out
+}
+
+// BuildSorted builds the trie of the entries, which must be sorted by key.
+func BuildSorted[T any](entries []KeyValue[T], combineValues func(t1 *T, t2 *T) *T) (*Trie[T], error) {
+	builder := NewBuilder[T](combineValues)
+	for _, entry := range entries {
+		if err := builder.Add(entry.Key, entry.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Build(), nil
+}
+
+// BuildParallel behaves like BuildSorted, but splits the entries by first rune and builds the children of the root
+// on workers goroutines.
+func BuildParallel[T any](entries []KeyValue[T], workers int, combineValues func(t1 *T, t2 *T) *T) (*Trie[T], error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	out := &Trie[T]{}
+
+	// the entries with an empty key are first since they're sorted
+	start := 0
+	for ; start < len(entries) && entries[start].Key == ""; start++ {
+		out.Value = combineValues(out.Value, entries[start].Value)
+	}
+
+	// every partition holds the entries starting with the same rune
+	type partition struct {
+		r     rune
+		start int
+		end   int
+	}
+
+	var partitions []partition
+	for i := start; i < len(entries); i++ {
+		if i > 0 && entries[i].Key < entries[i-1].Key {
+			return nil, ErrNotSorted
+		}
+
+		r, _ := utf8.DecodeRuneInString(entries[i].Key)
+		if len(partitions) == 0 || partitions[len(partitions)-1].r != r {
+			partitions = append(partitions, partition{r: r, start: i})
+		}
+		partitions[len(partitions)-1].end = i + 1
+	}
+
+	children := make([]*Trie[T], len(partitions))
+	partitionChannel := make(chan int, len(partitions))
+	for i := range partitions {
+		partitionChannel <- i
+	}
+	close(partitionChannel)
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer waitGroup.Done()
+
+			builder := NewBuilder[T](combineValues)
+			for index := range partitionChannel {
+				p := partitions[index]
+				// the child is built without the first rune, which is the same for the whole partition
+				for _, entry := range entries[p.start:p.end] {
+					_, size := utf8.DecodeRuneInString(entry.Key)
+					// the order of the entries was checked above
+					_ = builder.Add(entry.Key[size:], entry.Value)
+				}
+
+				children[index] = builder.Build()
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	out.children = make(map[rune]*Trie[T], len(partitions))
+	for i, p := range partitions {
+		out.children[p.r] = children[i]
+	}
+
+	return out, nil
+}
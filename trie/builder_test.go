@@ -0,0 +1,125 @@
+package trie
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	keys := []string{"", "a", "ab", "abc", "abc", "abd", "b", "ba", "⌘", "⌘a"}
+	values := make([]int, len(keys))
+	for i := range values {
+		values[i] = i
+	}
+
+	expected := New[int]()
+	builder := NewBuilder[int](Count)
+	for i, key := range keys {
+		expected.Insert(key, &values[i], Count)
+		if err := builder.Add(key, &values[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	built := builder.Build()
+	checkSameTrie(t, "", expected, built)
+
+	if *built.Step('a').Step('b').Step('c').Value != 7 {
+		t.Fatal("the values of the same key should be combined")
+	}
+
+	if built.Step('a').Step('b').Step('c').children != nil {
+		t.Fatal("a trie without children should not have a map")
+	}
+
+	// the built trie can still be updated
+	one := 1
+	built.Insert("abcd", &one, Count)
+	if *built.Step('a').Step('b').Step('c').Step('d').Value != 1 {
+		t.Fatal("the key should have been inserted")
+	}
+
+	// the builder was reset
+	if empty := builder.Build(); empty.Value != nil || empty.Step('a') != nil {
+		t.Fatal("the builder should be empty")
+	}
+}
+
+func TestBuilderNotSorted(t *testing.T) {
+	builder := NewBuilder[int](KeepFirst[int])
+	value := 0
+
+	if err := builder.Add("b", &value); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := builder.Add("a", &value); err != ErrNotSorted {
+		t.Fatalf("expected ErrNotSorted but got %v", err)
+	}
+
+	_, err := BuildParallel[int]([]KeyValue[int]{{Key: "ab", Value: &value}, {Key: "aa", Value: &value}}, 2, KeepFirst[int])
+	if err != ErrNotSorted {
+		t.Fatalf("expected ErrNotSorted but got %v", err)
+	}
+}
+
+func TestBuildParallel(t *testing.T) {
+	randGen := rand.New(rand.NewSource(42))
+	alphabet := []rune("abcé⌘")
+
+	entries := make([]KeyValue[int], 2000)
+	for i := range entries {
+		key := make([]rune, randGen.Intn(6))
+		for j := range key {
+			key[j] = alphabet[randGen.Intn(len(alphabet))]
+		}
+
+		value := 1
+		entries[i] = KeyValue[int]{Key: string(key), Value: &value}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	expected := New[int]()
+	for _, entry := range entries {
+		expected.Insert(entry.Key, entry.Value, Count)
+	}
+
+	built, err := BuildSorted[int](entries, Count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSameTrie(t, "", expected, built)
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		built, err := BuildParallel[int](entries, workers, Count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSameTrie(t, "", expected, built)
+	}
+}
+
+func checkSameTrie[T comparable](t *testing.T, key string, expected *Trie[T], actual *Trie[T]) {
+	t.Helper()
+
+	if (expected.Value == nil) != (actual.Value == nil) || (expected.Value != nil && *expected.Value != *actual.Value) {
+		t.Fatalf("unexpected value for key '%s'", key)
+	}
+
+	if len(expected.children) != len(actual.children) {
+		t.Fatalf("expected %d children but got %d for key '%s'", len(expected.children), len(actual.children), key)
+	}
+
+	for r, child := range expected.children {
+		actualChild := actual.Step(r)
+		if actualChild == nil {
+			t.Fatalf("missing key '%s'", key+string(r))
+		}
+
+		checkSameTrie(t, key+string(r), child, actualChild)
+	}
+}
@@ -0,0 +1,73 @@
+package trie
+
+// The functions below can be given to Insert to decide what happens when a key is inserted more than once.
+
+// KeepFirst keeps the value inserted first.
+func KeepFirst[T any](t1 *T, t2 *T) *T {
+	if t1 != nil {
+		return t1
+	}
+
+	return t2
+}
+
+// KeepLast keeps the value inserted last.
+func KeepLast[T any](t1 *T, t2 *T) *T {
+	if t2 != nil {
+		return t2
+	}
+
+	return t1
+}
+
+// Append keeps all the values inserted with the same key, in the order they were inserted.
+// The slice inserted first is extended with the values of the next ones.
+func Append[T any](t1 *[]T, t2 *[]T) *[]T {
+	if t1 != nil && t2 != nil {
+		*t1 = append(*t1, *t2...)
+		return t1
+	}
+
+	if t1 != nil {
+		return t1
+	}
+
+	return t2
+}
+
+// Union keeps all the distinct values inserted with the same key.
+// The set inserted first receives the values of the next ones.
+func Union[K comparable](t1 *map[K]struct{}, t2 *map[K]struct{}) *map[K]struct{} {
+	if t1 != nil && t2 != nil {
+		if *t1 == nil {
+			*t1 = make(map[K]struct{}, len(*t2))
+		}
+
+		for k := range *t2 {
+			(*t1)[k] = struct{}{}
+		}
+
+		return t1
+	}
+
+	if t1 != nil {
+		return t1
+	}
+
+	return t2
+}
+
+// Count adds up the counts inserted with the same key, the inserted values are never modified.
+// To count how many times every key is inserted, insert a pointer to 1.
+func Count(t1 *int, t2 *int) *int {
+	if t1 != nil && t2 != nil {
+		sum := *t1 + *t2
+		return &sum
+	}
+
+	if t1 != nil {
+		return t1
+	}
+
+	return t2
+}
@@ -0,0 +1,86 @@
+package trie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeepFirstAndLast(t *testing.T) {
+	first := "first"
+	last := "last"
+
+	firstTrie := New[string]()
+	firstTrie.Insert("a", &first, KeepFirst[string])
+	firstTrie.Insert("a", &last, KeepFirst[string])
+	if firstTrie.Step('a').Value != &first {
+		t.Fatal("KeepFirst should keep the first value")
+	}
+
+	lastTrie := New[string]()
+	lastTrie.Insert("a", &first, KeepLast[string])
+	lastTrie.Insert("a", &last, KeepLast[string])
+	if lastTrie.Step('a').Value != &last {
+		t.Fatal("KeepLast should keep the last value")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	testTrie := New[[]int]()
+	for i := 0; i < 3; i++ {
+		values := []int{i}
+		testTrie.Insert("a", &values, Append[int])
+	}
+
+	if !reflect.DeepEqual(*testTrie.Step('a').Value, []int{0, 1, 2}) {
+		t.Fatalf("unexpected values %v", *testTrie.Step('a').Value)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	testTrie := New[map[string]struct{}]()
+	for _, value := range []string{"x", "y", "x"} {
+		set := map[string]struct{}{value: {}}
+		testTrie.Insert("a", &set, Union[string])
+	}
+
+	expected := map[string]struct{}{"x": {}, "y": {}}
+	if !reflect.DeepEqual(*testTrie.Step('a').Value, expected) {
+		t.Fatalf("unexpected set %v", *testTrie.Step('a').Value)
+	}
+}
+
+func TestCount(t *testing.T) {
+	testTrie := New[int]()
+	one := 1
+	testTrie.Insert("a", &one, Count)
+	testTrie.Insert("a", &one, Count)
+	testTrie.Insert("b", &one, Count)
+	testTrie.Insert("a", &one, Count)
+
+	if *testTrie.Step('a').Value != 3 || *testTrie.Step('b').Value != 1 {
+		t.Fatalf("unexpected counts %d and %d", *testTrie.Step('a').Value, *testTrie.Step('b').Value)
+	}
+
+	if one != 1 {
+		t.Fatal("the inserted values should not be modified")
+	}
+}
+
+func TestMultiValue(t *testing.T) {
+	multiValue := NewMultiValue[int]()
+	multiValue.Add("bern", 1)
+	multiValue.Add("bern", 1)
+	multiValue.Add("berlin", 2)
+
+	if !reflect.DeepEqual(multiValue.Values("bern"), []int{1, 1}) {
+		t.Fatalf("duplicates should be kept, got %v", multiValue.Values("bern"))
+	}
+
+	if !reflect.DeepEqual(multiValue.Values("berlin"), []int{2}) {
+		t.Fatalf("unexpected values %v", multiValue.Values("berlin"))
+	}
+
+	if multiValue.Values("ber") != nil || multiValue.Values("paris") != nil {
+		t.Fatal("there should be no values")
+	}
+}
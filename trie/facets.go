@@ -0,0 +1,134 @@
+package trie
+
+import "unsafe"
+
+// Facets is a compact set of facet ids (a country, a type of place, etc...) stored as a bitmap.
+// The memory used by the set grows with the largest id, so ids should be allocated from 0.
+type Facets struct {
+	bits []uint64
+}
+
+func NewFacets(ids ...uint32) *Facets {
+	out := &Facets{}
+	for _, id := range ids {
+		out.Add(id)
+	}
+
+	return out
+}
+
+// Add the id to the set.
+func (f *Facets) Add(id uint32) {
+	word := int(id / 64)
+	if word >= len(f.bits) {
+		bits := make([]uint64, word+1)
+		copy(bits, f.bits)
+		f.bits = bits
+	}
+
+	f.bits[word] |= 1 << (id % 64)
+}
+
+// Has tells if the id is in the set, a nil set is empty.
+func (f *Facets) Has(id uint32) bool {
+	if f == nil {
+		return false
+	}
+
+	word := int(id / 64)
+	return word < len(f.bits) && f.bits[word]&(1<<(id%64)) != 0
+}
+
+// Union adds all the ids of other to the set, a nil other is empty.
+func (f *Facets) Union(other *Facets) {
+	if other == nil {
+		return
+	}
+
+	if len(other.bits) > len(f.bits) {
+		bits := make([]uint64, len(other.bits))
+		copy(bits, f.bits)
+		f.bits = bits
+	}
+
+	for i, word := range other.bits {
+		f.bits[i] |= word
+	}
+}
+
+// Intersects tells if at least one id is in both sets, a nil set is empty.
+func (f *Facets) Intersects(other *Facets) bool {
+	if f == nil || other == nil {
+		return false
+	}
+
+	for i := 0; i < len(f.bits) && i < len(other.bits); i++ {
+		if f.bits[i]&other.bits[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FacetedTrie is a trie whose nodes know the facets (a country, a type of place, etc...) of all the values of their
+// subtree, which lets a search skip the branches that don't contain any value with the facets it's looking for.
+// The facets are kept in a map next to the trie, so the tries that don't use facets don't pay for them.
+type FacetedTrie[T any] struct {
+	*Trie[T]
+	// facets maps the nodes to the facets of their subtree, the nodes without facets are not in the map
+	facets map[*Trie[T]]*Facets
+}
+
+func NewFaceted[T any]() *FacetedTrie[T] {
+	return &FacetedTrie[T]{
+		Trie:   New[T](),
+		facets: make(map[*Trie[T]]*Facets),
+	}
+}
+
+// InsertWithFacets inserts a string into the trie like Insert, and adds the facets of the value to every node on the
+// path from the root to the value. A nil facets is the empty set: the value is inserted, but no facet is added.
+func (ft *FacetedTrie[T]) InsertWithFacets(str string, value *T, facets *Facets, combineValues func(t1 *T, t2 *T) *T) {
+	crtTrie := ft.Trie
+	ft.addFacets(crtTrie, facets)
+	for _, r := range []rune(str) {
+		crtTrie = crtTrie.StepOrCreate(r)
+		ft.addFacets(crtTrie, facets)
+	}
+	crtTrie.Value = combineValues(crtTrie.Value, value)
+}
+
+func (ft *FacetedTrie[T]) addFacets(node *Trie[T], facets *Facets) {
+	if facets == nil {
+		return
+	}
+
+	nodeFacets, ok := ft.facets[node]
+	if !ok {
+		nodeFacets = &Facets{}
+		ft.facets[node] = nodeFacets
+	}
+
+	nodeFacets.Union(facets)
+}
+
+// Facets returns the facets of all the values in the subtree of a node of the trie, or nil if none were inserted
+// with InsertWithFacets. The returned set should not be modified.
+func (ft *FacetedTrie[T]) Facets(node *Trie[T]) *Facets {
+	return ft.facets[node]
+}
+
+// Stats behaves like Trie.Stats, and also counts the memory used by the facets.
+func (ft *FacetedTrie[T]) Stats() Stats {
+	stats := ft.Trie.Stats()
+
+	var node *Trie[T]
+	var facets *Facets
+	stats.Bytes.Facets = mapBytes(len(ft.facets), int(unsafe.Sizeof(node)), int(unsafe.Sizeof(facets)))
+	for _, f := range ft.facets {
+		stats.Bytes.Facets += allocationBytes(int(unsafe.Sizeof(*f))) + allocationBytes(8*cap(f.bits))
+	}
+
+	return stats
+}
@@ -0,0 +1,90 @@
+package trie
+
+import "testing"
+
+func TestFacets(t *testing.T) {
+	facets := NewFacets(1, 70)
+
+	if !facets.Has(1) || !facets.Has(70) || facets.Has(0) || facets.Has(2) || facets.Has(1000) {
+		t.Fatal("unexpected facets")
+	}
+
+	other := NewFacets(2)
+	if facets.Intersects(other) || other.Intersects(facets) {
+		t.Fatal("the sets should not intersect")
+	}
+
+	other.Union(facets)
+	if !other.Has(1) || !other.Has(2) || !other.Has(70) {
+		t.Fatal("the union should hold all the ids")
+	}
+
+	if !facets.Intersects(other) || !other.Intersects(facets) {
+		t.Fatal("the sets should intersect")
+	}
+
+	if NewFacets().Intersects(facets) {
+		t.Fatal("an empty set should not intersect")
+	}
+}
+
+func TestInsertWithFacets(t *testing.T) {
+	testTrie := NewFaceted[string]()
+
+	bern := "bern"
+	berlin := "berlin"
+	testTrie.InsertWithFacets(bern, &bern, NewFacets(0), KeepLast[string])
+	testTrie.InsertWithFacets(berlin, &berlin, NewFacets(1), KeepLast[string])
+
+	if !testTrie.Facets(testTrie.Trie).Has(0) || !testTrie.Facets(testTrie.Trie).Has(1) {
+		t.Fatal("the root should hold all the facets")
+	}
+
+	ber := testTrie.Step('b').Step('e').Step('r')
+	if !testTrie.Facets(ber).Has(0) || !testTrie.Facets(ber).Has(1) {
+		t.Fatal("the shared prefix should hold all the facets")
+	}
+
+	bernStep := ber.Step('n')
+	if !testTrie.Facets(bernStep).Has(0) || testTrie.Facets(bernStep).Has(1) || bernStep.Value != &bern {
+		t.Fatal("bern should only hold its own facet")
+	}
+
+	berl := ber.Step('l')
+	if testTrie.Facets(berl).Has(0) || !testTrie.Facets(berl).Has(1) {
+		t.Fatal("berlin should only hold its own facet")
+	}
+
+	other := "other"
+	testTrie.Insert(other, &other, KeepLast[string])
+	if testTrie.Facets(testTrie.Step('o')) != nil {
+		t.Fatal("a value inserted without facets should not have any facet")
+	}
+}
+
+func TestNilFacets(t *testing.T) {
+	var facets *Facets
+	if facets.Has(0) || facets.Intersects(NewFacets(0)) || NewFacets(0).Intersects(facets) {
+		t.Fatal("a nil set should be empty")
+	}
+
+	union := NewFacets(1)
+	union.Union(facets)
+	if !union.Has(1) || union.Has(0) {
+		t.Fatal("the union with a nil set should not change the set")
+	}
+
+	testTrie := NewFaceted[string]()
+	bern := "bern"
+	testTrie.InsertWithFacets(bern, &bern, nil, KeepFirst[string])
+	testTrie.InsertWithFacets("berlin", &bern, NewFacets(1), KeepFirst[string])
+
+	bernStep := testTrie.Step('b').Step('e').Step('r').Step('n')
+	if bernStep.Value != &bern || testTrie.Facets(bernStep).Intersects(NewFacets(1)) || !testTrie.Facets(testTrie.Trie).Has(1) {
+		t.Fatal("a value inserted with nil facets should be inserted without any facet")
+	}
+
+	if testTrie.Facets(bernStep) != nil {
+		t.Fatal("no facets should of been allocated for bern")
+	}
+}
@@ -0,0 +1,146 @@
+package trie
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// sizeClasses are the sizes of the small objects of the Go allocator, an allocation is rounded up to the next one.
+var sizeClasses = []int{
+	8, 16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240, 256, 288, 320, 352, 384, 416, 448,
+	480, 512, 576, 640, 704, 768, 896, 1024, 1152, 1280, 1408, 1536, 1792, 2048, 2304, 2688, 3072, 3200, 3456, 4096,
+	4864, 5376, 6144, 6528, 6784, 6912, 8192, 9472, 9728, 10240, 10880, 12288, 13568, 14336, 16384, 18432, 19072,
+	20480, 21760, 24576, 27264, 28672, 32768,
+}
+
+// pageSize is the size of the pages the large objects are rounded up to.
+const pageSize = 8192
+
+// allocationBytes returns the memory the Go allocator uses for an object of size bytes.
+func allocationBytes(size int) int {
+	if size == 0 {
+		return 0
+	}
+
+	for _, class := range sizeClasses {
+		if size <= class {
+			return class
+		}
+	}
+
+	return (size + pageSize - 1) / pageSize * pageSize
+}
+
+// swissMaps is true if the running Go version uses Swiss tables for its maps, which is the case since Go 1.24.
+var swissMaps = hasSwissMaps(runtime.Version())
+
+// hasSwissMaps tells if the Go version, as returned by runtime.Version, uses Swiss tables. The versions it can't
+// parse, like the development ones, are assumed to be recent.
+func hasSwissMaps(version string) bool {
+	if !strings.HasPrefix(version, "go1.") {
+		return true
+	}
+
+	minor := strings.TrimPrefix(version, "go1.")
+	digits := 0
+	for digits < len(minor) && minor[digits] >= '0' && minor[digits] <= '9' {
+		digits++
+	}
+
+	n, err := strconv.Atoi(minor[:digits])
+	return err != nil || n >= 24
+}
+
+// The layout of the maps since Go 1.24.
+const (
+	// mapHeaderSize is the size of the header of a Go map, before and since Go 1.24
+	mapHeaderSize = 48
+	// groupSlots is the number of entries of a group, and of a bucket before Go 1.24
+	groupSlots = 8
+	// maxAvgGroupLoad is the number of entries of a group a table holds on average before growing
+	maxAvgGroupLoad = 7
+	// maxTableCapacity is the number of slots above which a table is split in two instead of growing
+	maxTableCapacity = 1024
+	// tableSize is the size of the struct describing a table
+	tableSize = 32
+)
+
+// The layout of the maps before Go 1.24.
+const (
+	// mapLoadFactor is the average number of entries per bucket above which a map grows
+	mapLoadFactor = 6.5
+)
+
+// mapBytes estimates the memory used by a map with entries keys and values of the given sizes, for the maps of the
+// running Go version.
+func mapBytes(entries int, keySize int, valueSize int) int {
+	if swissMaps {
+		return swissMapBytes(entries, keySize, valueSize)
+	}
+
+	return bucketMapBytes(entries, keySize, valueSize)
+}
+
+// swissMapBytes estimates the memory used by a Swiss table map. Up to 8 entries, the map is a single group: a control
+// word followed by 8 slots. Above, it's a directory of tables, each table having a power of two number of groups and
+// being split once it reaches 1024 slots.
+func swissMapBytes(entries int, keySize int, valueSize int) int {
+	if entries == 0 {
+		// the group is only allocated on the first insert
+		return mapHeaderSize
+	}
+
+	pointerSize := int(unsafe.Sizeof(uintptr(0)))
+	slotSize := align(align(keySize, pointerSize)+valueSize, pointerSize)
+	groupSize := pointerSize + groupSlots*slotSize
+
+	if entries <= groupSlots {
+		return mapHeaderSize + allocationBytes(groupSize)
+	}
+
+	capacity := 2 * groupSlots
+	for entries > capacity*maxAvgGroupLoad/groupSlots {
+		capacity *= 2
+	}
+
+	tables := 1
+	for capacity > maxTableCapacity {
+		tables *= 2
+		capacity /= 2
+	}
+
+	groups := capacity / groupSlots
+	return mapHeaderSize + allocationBytes(tables*pointerSize) +
+		tables*(allocationBytes(tableSize)+allocationBytes(groups*groupSize))
+}
+
+// bucketMapBytes estimates the memory used by a map before Go 1.24: a header and a power of two number of buckets,
+// each holding 8 keys, 8 values, their hashes and a pointer to an overflow bucket. Past 16 buckets, some overflow
+// buckets are allocated upfront.
+func bucketMapBytes(entries int, keySize int, valueSize int) int {
+	if entries == 0 {
+		// the buckets are only allocated on the first insert
+		return mapHeaderSize
+	}
+
+	buckets := 1
+	for float64(entries) > mapLoadFactor*float64(buckets) {
+		buckets *= 2
+	}
+
+	if buckets >= 16 {
+		buckets += buckets / 16
+	}
+
+	pointerSize := int(unsafe.Sizeof(uintptr(0)))
+	bucketSize := align(groupSlots*(1+keySize)+groupSlots*valueSize+pointerSize, pointerSize)
+
+	return mapHeaderSize + allocationBytes(buckets*bucketSize)
+}
+
+// align rounds size up to a multiple of alignment.
+func align(size int, alignment int) int {
+	return (size + alignment - 1) / alignment * alignment
+}
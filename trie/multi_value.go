@@ -0,0 +1,29 @@
+package trie
+
+// MultiValue is a trie keeping every value inserted with the same key, duplicates included.
+// The embedded trie can be given to fuzzy.Search like any other trie, every match is then a slice of values.
+type MultiValue[T any] struct {
+	*Trie[[]T]
+}
+
+func NewMultiValue[T any]() *MultiValue[T] {
+	return &MultiValue[T]{
+		Trie: New[[]T](),
+	}
+}
+
+// Add a value to the values of str.
+func (multiValue *MultiValue[T]) Add(str string, value T) {
+	values := []T{value}
+	multiValue.Trie.Insert(str, &values, Append[T])
+}
+
+// Values returns all the values added with str in the order they were added, or nil if there is none.
+func (multiValue *MultiValue[T]) Values(str string) []T {
+	values := multiValue.Trie.Get(str)
+	if values == nil {
+		return nil
+	}
+
+	return *values
+}
@@ -0,0 +1,60 @@
+package trie
+
+import "unicode/utf8"
+
+// PrefixMatch is a key of the trie that is a prefix of the string looked up.
+type PrefixMatch[T any] struct {
+	Prefix string
+	Value  *T
+}
+
+// LongestPrefix returns the longest key of the trie that is a prefix of str, and its value.
+// If no key is a prefix of str, the value is nil.
+func (trie *Trie[T]) LongestPrefix(str string) (string, *T) {
+	prefix := ""
+	value := trie.Value
+
+	crtTrie := trie
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		i += size
+
+		crtTrie = crtTrie.Step(r)
+		if crtTrie == nil {
+			break
+		}
+
+		if crtTrie.Value != nil {
+			prefix = str[:i]
+			value = crtTrie.Value
+		}
+	}
+
+	return prefix, value
+}
+
+// AllPrefixes returns all the keys of the trie that are a prefix of str with their value, from the shortest to the
+// longest.
+func (trie *Trie[T]) AllPrefixes(str string) []PrefixMatch[T] {
+	var out []PrefixMatch[T]
+	if trie.Value != nil {
+		out = append(out, PrefixMatch[T]{Prefix: "", Value: trie.Value})
+	}
+
+	crtTrie := trie
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		i += size
+
+		crtTrie = crtTrie.Step(r)
+		if crtTrie == nil {
+			break
+		}
+
+		if crtTrie.Value != nil {
+			out = append(out, PrefixMatch[T]{Prefix: str[:i], Value: crtTrie.Value})
+		}
+	}
+
+	return out
+}
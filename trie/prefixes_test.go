@@ -0,0 +1,51 @@
+package trie
+
+import "testing"
+
+func TestLongestPrefix(t *testing.T) {
+	testTrie := New[string]()
+	for _, key := range []string{"new", "new york", "york", "⌘"} {
+		value := key
+		testTrie.Insert(key, &value, KeepFirst[string])
+	}
+
+	for str, expected := range map[string]string{
+		"new york city": "new york",
+		"new yor":       "new",
+		"newark":        "new",
+		"ne":            "",
+		"⌘⌘":            "⌘",
+	} {
+		prefix, value := testTrie.LongestPrefix(str)
+		if prefix != expected || (expected != "" && (value == nil || *value != expected)) || (expected == "" && value != nil) {
+			t.Fatalf("unexpected longest prefix '%s' for '%s'", prefix, str)
+		}
+	}
+
+	root := ""
+	testTrie.Insert("", &root, KeepFirst[string])
+	if prefix, value := testTrie.LongestPrefix("paris"); prefix != "" || value != &root {
+		t.Fatal("the empty key is a prefix of every string")
+	}
+}
+
+func TestAllPrefixes(t *testing.T) {
+	testTrie := New[int]()
+	for i, key := range []string{"a", "ab", "abcd", "b"} {
+		value := i
+		testTrie.Insert(key, &value, KeepFirst[int])
+	}
+
+	matches := testTrie.AllPrefixes("abcde")
+	if len(matches) != 3 || matches[0].Prefix != "a" || matches[1].Prefix != "ab" || matches[2].Prefix != "abcd" {
+		t.Fatalf("unexpected matches %v", matches)
+	}
+
+	if *matches[2].Value != 2 {
+		t.Fatal("unexpected value")
+	}
+
+	if len(testTrie.AllPrefixes("c")) != 0 {
+		t.Fatal("there should be no match")
+	}
+}
@@ -0,0 +1,85 @@
+package trie
+
+import "unsafe"
+
+// Stats describes the shape of a trie and estimates the memory it uses.
+type Stats struct {
+	// Nodes is the number of tries, the root included
+	Nodes int
+	// Values is the number of tries holding a value
+	Values int
+	// MaxDepth is the length in runes of the longest path from the root
+	MaxDepth int
+	// FanOut[i] is the number of tries with i children
+	FanOut []int
+	// Bytes estimates the memory used by the trie
+	Bytes MemoryStats
+}
+
+// MemoryStats estimates the memory used by the different parts of a trie, in bytes.
+type MemoryStats struct {
+	// Nodes is the memory used by the Trie structs
+	Nodes int
+	// Maps is the memory used by the maps of children, modelled on the map layout of the running Go version
+	Maps int
+	// Facets is the memory used by the facets of a FacetedTrie, it's 0 for a Trie
+	Facets int
+	// Values is the memory used by the values themselves, only counting the size of T, not what T points to
+	Values int
+}
+
+// Total returns the estimated memory used by the whole trie.
+func (m MemoryStats) Total() int {
+	return m.Nodes + m.Maps + m.Facets + m.Values
+}
+
+// Stats walks the whole trie, so it takes about as long as visiting every node.
+// The memory is an estimate: the structs and the maps are rounded up to the size classes of the allocator, but what
+// the values point to is not counted.
+func (trie *Trie[T]) Stats() Stats {
+	var stats Stats
+	var value T
+	nodeSize := allocationBytes(int(unsafe.Sizeof(*trie)))
+	valueSize := int(unsafe.Sizeof(value))
+	var r rune
+	runeSize := int(unsafe.Sizeof(r))
+	childSize := int(unsafe.Sizeof(trie))
+
+	type depthTrie struct {
+		trie  *Trie[T]
+		depth int
+	}
+
+	stack := []depthTrie{{trie: trie}}
+	for len(stack) > 0 {
+		crt := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		stats.Nodes++
+		stats.Bytes.Nodes += nodeSize
+		if crt.trie.children != nil {
+			stats.Bytes.Maps += mapBytes(len(crt.trie.children), runeSize, childSize)
+		}
+
+		if crt.trie.Value != nil {
+			stats.Values++
+			stats.Bytes.Values += valueSize
+		}
+
+		if crt.depth > stats.MaxDepth {
+			stats.MaxDepth = crt.depth
+		}
+
+		fanOut := len(crt.trie.children)
+		for len(stats.FanOut) <= fanOut {
+			stats.FanOut = append(stats.FanOut, 0)
+		}
+		stats.FanOut[fanOut]++
+
+		for _, child := range crt.trie.children {
+			stack = append(stack, depthTrie{trie: child, depth: crt.depth + 1})
+		}
+	}
+
+	return stats
+}
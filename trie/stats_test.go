@@ -0,0 +1,113 @@
+package trie
+
+import (
+	"math/rand"
+	"reflect"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+func TestStats(t *testing.T) {
+	testTrie := New[int]()
+	for i, key := range []string{"bern", "berlin", "basel", "", "⌘"} {
+		value := i
+		testTrie.Insert(key, &value, KeepFirst[int])
+	}
+
+	stats := testTrie.Stats()
+
+	// root, b, be, ber, bern, berl, berli, berlin, ba, bas, base, basel, ⌘
+	if stats.Nodes != 13 || stats.Values != 5 || stats.MaxDepth != 6 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// 5 leaves, 6 tries with one child, and the root, b and ber with two children
+	if !reflect.DeepEqual(stats.FanOut, []int{4, 6, 3}) {
+		t.Fatalf("unexpected fan out %v", stats.FanOut)
+	}
+
+	if stats.Bytes.Nodes <= 0 || stats.Bytes.Maps <= 0 || stats.Bytes.Values != 5*int(unsafe.Sizeof(0)) || stats.Bytes.Facets != 0 {
+		t.Fatalf("unexpected memory stats %+v", stats.Bytes)
+	}
+
+	if stats.Bytes.Total() != stats.Bytes.Nodes+stats.Bytes.Maps+stats.Bytes.Values {
+		t.Fatal("unexpected total")
+	}
+
+	faceted := NewFaceted[int]()
+	faceted.InsertWithFacets("zurich", nil, NewFacets(1), KeepFirst[int])
+	facetedStats := faceted.Stats()
+	if facetedStats.Bytes.Facets == 0 || facetedStats.Nodes != 7 {
+		t.Fatalf("the facets should of been counted, got %+v", facetedStats)
+	}
+}
+
+func TestStatsBuilder(t *testing.T) {
+	entries := []KeyValue[int]{{Key: "a"}, {Key: "ab"}, {Key: "b"}}
+	for i := range entries {
+		value := i
+		entries[i].Value = &value
+	}
+
+	built, err := BuildSorted[int](entries, KeepFirst[int])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inserted := New[int]()
+	for _, entry := range entries {
+		inserted.Insert(entry.Key, entry.Value, KeepFirst[int])
+	}
+
+	// the leaves of a built trie don't have a map
+	if built.Stats().Bytes.Maps >= inserted.Stats().Bytes.Maps {
+		t.Fatal("the built trie should use less memory for its maps")
+	}
+}
+
+func TestStatsMatchesHeap(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	before := heapAlloc()
+
+	testTrie := New[int]()
+	for i := 0; i < 50000; i++ {
+		key := make([]rune, 4+random.Intn(4))
+		for j := range key {
+			key[j] = 'a' + rune(random.Intn(26))
+		}
+		testTrie.Insert(string(key), nil, KeepFirst[int])
+	}
+
+	measured := float64(heapAlloc() - before)
+	estimated := float64(testTrie.Stats().Bytes.Total())
+	runtime.KeepAlive(testTrie)
+
+	if estimated < 0.95*measured || estimated > 1.05*measured {
+		t.Fatalf("estimated %.0f bytes but the heap grew by %.0f bytes", estimated, measured)
+	}
+}
+
+func heapAlloc() uint64 {
+	runtime.GC()
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return memStats.HeapAlloc
+}
+
+func TestHasSwissMaps(t *testing.T) {
+	versions := map[string]bool{
+		"go1.18":                  false,
+		"go1.23.4":                false,
+		"go1.24rc1":               true,
+		"go1.24.0":                true,
+		"go1.27.1":                true,
+		"devel go1.25-1234abcdef": true,
+	}
+
+	for version, expected := range versions {
+		if hasSwissMaps(version) != expected {
+			t.Fatalf("unexpected result for %s", version)
+		}
+	}
+}
@@ -25,6 +25,11 @@ func (trie *Trie[T]) Insert(str string, value *T, combineValues func(t1 *T, t2 *
 	crtTrie.Value = combineValues(crtTrie.Value, value)
 }
 
+// GetValue returns the value of this trie, it lets fuzzy.SearchNode read the values of any kind of node.
+func (trie *Trie[T]) GetValue() *T {
+	return trie.Value
+}
+
 // Step out with the rune r and return the next Trie or nil if it does not exist.
 func (trie *Trie[T]) Step(r rune) *Trie[T] {
 	return trie.children[r]
@@ -37,6 +42,11 @@ func (trie *Trie[T]) StepOrCreate(r rune) *Trie[T] {
 		return step
 	}
 
+	if trie.children == nil {
+		// the tries made by a Builder don't have a map if they have no children
+		trie.children = make(map[rune]*Trie[T])
+	}
+
 	out := &Trie[T]{
 		children: make(map[rune]*Trie[T]),
 		Value:    nil,
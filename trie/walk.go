@@ -0,0 +1,98 @@
+package trie
+
+import "sort"
+
+// IterateSorted iterates over all the children of this trie by increasing rune.
+func (trie *Trie[T]) IterateSorted(iterationFunction func(r rune, trie *Trie[T])) {
+	runes := make([]rune, 0, len(trie.children))
+	for r := range trie.children {
+		runes = append(runes, r)
+	}
+
+	sort.Slice(runes, func(i, j int) bool {
+		return runes[i] < runes[j]
+	})
+
+	for _, r := range runes {
+		iterationFunction(r, trie.children[r])
+	}
+}
+
+// Prefix returns the subtree of all the keys starting with prefix, or nil if there is none.
+func (trie *Trie[T]) Prefix(prefix string) *Trie[T] {
+	crtTrie := trie
+	for _, r := range prefix {
+		crtTrie = crtTrie.Step(r)
+		if crtTrie == nil {
+			return nil
+		}
+	}
+
+	return crtTrie
+}
+
+// Get returns the value of the key, or nil if the key is not in the trie.
+func (trie *Trie[T]) Get(key string) *T {
+	crtTrie := trie.Prefix(key)
+	if crtTrie == nil {
+		return nil
+	}
+
+	return crtTrie.Value
+}
+
+// HasPrefix tells if at least one key of the trie starts with prefix.
+func (trie *Trie[T]) HasPrefix(prefix string) bool {
+	crtTrie := trie.Prefix(prefix)
+	if crtTrie == nil {
+		return false
+	}
+
+	// a trie may have been created without any value below it, by StepOrCreate for example
+	return !crtTrie.Walk(func(string, *T) bool {
+		return false
+	})
+}
+
+// Walk calls walkFunction with every key of the trie and its value, depth first, in no particular order.
+// The walk stops as soon as walkFunction returns false, Walk returns false if it was stopped that way.
+func (trie *Trie[T]) Walk(walkFunction func(key string, value *T) bool) bool {
+	return trie.walk(nil, false, walkFunction)
+}
+
+// WalkSorted behaves like Walk, but the keys are visited in increasing order.
+func (trie *Trie[T]) WalkSorted(walkFunction func(key string, value *T) bool) bool {
+	return trie.walk(nil, true, walkFunction)
+}
+
+// WalkPrefix calls walkFunction with every key starting with prefix and its value, in increasing order of keys.
+// Like Walk, it stops as soon as walkFunction returns false and then returns false.
+func (trie *Trie[T]) WalkPrefix(prefix string, walkFunction func(key string, value *T) bool) bool {
+	crtTrie := trie.Prefix(prefix)
+	if crtTrie == nil {
+		return true
+	}
+
+	return crtTrie.walk([]rune(prefix), true, walkFunction)
+}
+
+func (trie *Trie[T]) walk(key []rune, sorted bool, walkFunction func(key string, value *T) bool) bool {
+	if trie.Value != nil && !walkFunction(string(key), trie.Value) {
+		return false
+	}
+
+	keepWalking := true
+	visit := func(r rune, child *Trie[T]) {
+		if keepWalking {
+			keepWalking = child.walk(append(key, r), sorted, walkFunction)
+		}
+	}
+
+	if sorted {
+		trie.IterateSorted(visit)
+	} else {
+		trie.Iterate(visit)
+	}
+
+	return keepWalking
+}
@@ -0,0 +1,129 @@
+package trie
+
+import (
+	"strings"
+	"testing"
+)
+
+func newWalkTestTrie() *Trie[string] {
+	testTrie := New[string]()
+	for _, key := range []string{"bern", "", "berlin", "basel", "⌘", "be", "zurich"} {
+		value := strings.ToUpper(key)
+		testTrie.Insert(key, &value, KeepFirst[string])
+	}
+
+	// a branch without any value
+	testTrie.StepOrCreate('x').StepOrCreate('y')
+
+	return testTrie
+}
+
+func TestWalkSorted(t *testing.T) {
+	testTrie := newWalkTestTrie()
+
+	var keys []string
+	completed := testTrie.WalkSorted(func(key string, value *string) bool {
+		if *value != strings.ToUpper(key) {
+			t.Fatalf("unexpected value %s for %s", *value, key)
+		}
+
+		keys = append(keys, key)
+		return true
+	})
+
+	if !completed || strings.Join(keys, "|") != "|basel|be|berlin|bern|zurich|⌘" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	keys = keys[:0]
+	completed = testTrie.WalkSorted(func(key string, value *string) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+
+	if completed || strings.Join(keys, "|") != "|basel|be" {
+		t.Fatalf("the walk should of stopped early, got %v", keys)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	testTrie := newWalkTestTrie()
+
+	seen := make(map[string]struct{})
+	testTrie.Walk(func(key string, value *string) bool {
+		seen[key] = struct{}{}
+		return true
+	})
+
+	if len(seen) != 7 {
+		t.Fatalf("unexpected keys %v", seen)
+	}
+
+	count := 0
+	if testTrie.Walk(func(key string, value *string) bool {
+		count++
+		return false
+	}) || count != 1 {
+		t.Fatal("the walk should of stopped after the first key")
+	}
+}
+
+func TestWalkPrefix(t *testing.T) {
+	testTrie := newWalkTestTrie()
+
+	var keys []string
+	testTrie.WalkPrefix("ber", func(key string, value *string) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	if strings.Join(keys, "|") != "berlin|bern" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	if !testTrie.WalkPrefix("paris", func(key string, value *string) bool {
+		t.Fatal("there should be no key")
+		return true
+	}) {
+		t.Fatal("an empty walk is complete")
+	}
+}
+
+func TestGetAndPrefix(t *testing.T) {
+	testTrie := newWalkTestTrie()
+
+	if value := testTrie.Get("bern"); value == nil || *value != "BERN" {
+		t.Fatal("unexpected value for bern")
+	}
+
+	if testTrie.Get("ber") != nil || testTrie.Get("berne") != nil || testTrie.Get("xy") != nil {
+		t.Fatal("there should be no value")
+	}
+
+	if testTrie.Prefix("ber") != testTrie.Step('b').Step('e').Step('r') || testTrie.Prefix("bex") != nil {
+		t.Fatal("unexpected subtree")
+	}
+
+	if testTrie.Prefix("") != testTrie {
+		t.Fatal("the empty prefix is the whole trie")
+	}
+
+	for prefix, expected := range map[string]bool{"": true, "ber": true, "bern": true, "berne": false, "x": false, "⌘": true} {
+		if testTrie.HasPrefix(prefix) != expected {
+			t.Fatalf("HasPrefix(%s) should be %t", prefix, expected)
+		}
+	}
+}
+
+func TestIterateSorted(t *testing.T) {
+	testTrie := newWalkTestTrie()
+
+	var runes []rune
+	testTrie.IterateSorted(func(r rune, _ *Trie[string]) {
+		runes = append(runes, r)
+	})
+
+	if string(runes) != "bxz⌘" {
+		t.Fatalf("unexpected runes %s", string(runes))
+	}
+}